@@ -21,7 +21,6 @@ import (
 
 	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
 	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
-	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
 )
 
 var (
@@ -29,7 +28,7 @@ var (
 )
 
 func init() {
-	addRepositoryCmd.PersistentFlags().StringVarP(&optAddRepoType, "type", "t", "controller", "repository type")
+	addRepositoryCmd.PersistentFlags().StringVarP(&optAddRepoType, "type", "t", "controller", "repository type (core|controller|tooling)")
 }
 
 var addRepositoryCmd = &cobra.Command{
@@ -40,10 +39,19 @@ var addRepositoryCmd = &cobra.Command{
 }
 
 func addRepository(cmd *cobra.Command, args []string) error {
+	repoType, err := repository.GetRepositoryTypeFromString(optAddRepoType)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.Load(ackConfigPath)
 	if err != nil {
 		return err
 	}
+	repos, err := cfg.Repositories.ByType(repoType.String())
+	if err != nil {
+		return err
+	}
 
 	repoManager, err := repository.NewManager(cfg)
 	if err != nil {
@@ -54,12 +62,12 @@ func addRepository(cmd *cobra.Command, args []string) error {
 		service = strings.ToLower(service)
 
 		// Check it doesn't already exist in the configuration
-		if util.InStrings(service, cfg.Repositories.Services) {
-			fmt.Printf("repository for service %s has already been added\n", service)
+		if config.FindRepository(*repos, service) != nil {
+			fmt.Printf("%s repository %s has already been added\n", repoType, service)
 			continue
 		}
 
-		_, err := repoManager.AddRepository(service, repository.GetRepositoryTypeFromString(optAddRepoType))
+		_, err := repoManager.AddRepository(service, repoType)
 		if err != nil {
 			return err
 		}
@@ -69,7 +77,7 @@ func addRepository(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		cfg.Repositories.Services = append(cfg.Repositories.Services, service)
+		*repos = append(*repos, config.RepositoryConfig{Name: service})
 		if err := config.Save(cfg, ackConfigPath); err != nil {
 			return err
 		}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	adoptTableHeaderColumns = []string{"Name", "Type", "Path", "Action"}
+
+	optAdoptMove   bool
+	optAdoptDryRun bool
+)
+
+func init() {
+	adoptCmd.PersistentFlags().BoolVar(&optAdoptMove, "move", false, "move the adopted repositories into the root directory")
+	adoptCmd.PersistentFlags().BoolVar(&optAdoptDryRun, "dry-run", false, "only display the repositories that would be adopted")
+}
+
+var adoptCmd = &cobra.Command{
+	Use:     "adopt <directory>",
+	RunE:    adoptRepositories,
+	Args:    cobra.ExactArgs(1),
+	Short:   "Adopt existing local checkouts of ACK repositories",
+	Example: "ackdev adopt $GOPATH/src/github.com --move",
+}
+
+func adoptRepositories(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Github.Username == "" {
+		return fmt.Errorf("github username is required to identify forks, please run `ackdev edit config`")
+	}
+
+	repoManager, err := repository.NewManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	scanDir, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	candidates, err := repoManager.Scan(scanDir)
+	if err != nil {
+		return err
+	}
+
+	tw := newTable()
+	defer tw.Render()
+	tw.SetHeader(adoptTableHeaderColumns)
+
+	adopted := 0
+	for _, candidate := range candidates {
+		if candidate.Err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping %s: %v\n", candidate.Path, candidate.Err)
+			continue
+		}
+		action := "record location"
+		managed := filepath.Join(cfg.RootDirectory, candidate.Name) == candidate.Path
+		if managed {
+			action = "already managed"
+		} else if optAdoptMove {
+			action = "move"
+		}
+
+		// managed repositories already follow the conventions, their remotes
+		// are left untouched.
+		if !optAdoptDryRun && !managed {
+			_, err := repoManager.Adopt(candidate, optAdoptMove)
+			if err != nil {
+				action = fmt.Sprintf("error: %v", err)
+			} else {
+				adopted++
+			}
+		}
+		tw.Append([]string{candidate.Name, candidate.Type.String(), candidate.Path, action})
+	}
+
+	if optAdoptDryRun || adopted == 0 {
+		return nil
+	}
+	return config.Save(cfg, ackConfigPath)
+}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/image"
+)
+
+var (
+	optBuildImageTag  string
+	optBuildImageLoad bool
+)
+
+func init() {
+	buildImageCmd.PersistentFlags().StringVar(&optBuildImageTag, "tag", "", "image tag, defaults to a tag derived from the controller HEAD commit")
+	buildImageCmd.PersistentFlags().BoolVar(&optBuildImageLoad, "load", false, "load the image into the kind cluster")
+	buildImageCmd.PersistentFlags().StringVar(&optClusterName, "cluster", "", "kind cluster name, overrides the configured name")
+}
+
+var buildImageCmd = &cobra.Command{
+	Use:     "image <service>",
+	Aliases: []string{"img"},
+	RunE:    buildImage,
+	PreRunE: requireDependencies("docker"),
+	Args:    cobra.ExactArgs(1),
+	Short:   "Build a service controller image from its local checkout",
+	Example: "ackdev build image s3 --load",
+}
+
+func buildImage(cmd *cobra.Command, args []string) error {
+	cfg, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+	codeGeneratorPath, err := coreRepositoryPath(cfg, codeGeneratorRepositoryName)
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+
+	builder := image.NewBuilder(codeGeneratorPath, image.WithOutput(out))
+	dockerfile := builder.Dockerfile(repo)
+	if _, err := os.Stat(dockerfile); err != nil {
+		return fmt.Errorf("cannot find %s, please run `ackdev ensure repos`", dockerfile)
+	}
+
+	ctx := cmd.Context()
+	record, err := builder.Build(ctx, repo, optBuildImageTag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("built image %s\n", record.Image)
+
+	if optBuildImageLoad {
+		err = checkDependencies(ctx, "kind", "kubectl")
+		if err != nil {
+			return err
+		}
+		clusterManager := newClusterManager(cfg, out)
+		err = clusterManager.EnsureRunning(ctx)
+		if err != nil {
+			return err
+		}
+		err = clusterManager.LoadImage(ctx, record.Image)
+		if err != nil {
+			return err
+		}
+		clusterID, err := clusterManager.ID(ctx)
+		if err != nil {
+			return err
+		}
+		record.ClusterIDs = append(record.ClusterIDs, clusterID)
+		fmt.Printf("loaded image %s into cluster %s\n", record.Image, clusterManager.Name())
+	}
+
+	return image.NewStore(stateDirectory(cfg)).Put(repo.Name, record)
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/bump"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+var (
+	bumpTableHeaderColumns = []string{"Name", "Previous", "Status", "Branch", "Error"}
+
+	optBumpFilterExpression string
+	optBumpSkipGenerate     bool
+)
+
+func init() {
+	bumpRuntimeCmd.PersistentFlags().StringVarP(&optBumpFilterExpression, "filter", "f", "", "filter expression")
+	bumpRuntimeCmd.PersistentFlags().BoolVar(&optBumpSkipGenerate, "skip-generate", false, "do not regenerate the controllers after updating the runtime")
+}
+
+var bumpRuntimeCmd = &cobra.Command{
+	Use:     "runtime <version>",
+	RunE:    bumpRuntime,
+	PreRunE: checkBumpRuntimeDependencies,
+	Args:    cobra.ExactArgs(1),
+	Short:   "Update the runtime version required by the controllers, in a new branch",
+	Example: "ackdev bump runtime v0.15.2 -f name=s3-controller",
+}
+
+// bumpRecord is the result of a runtime bump in a repository.
+type bumpRecord struct {
+	repo   *repository.Repository
+	result *bump.Result
+}
+
+// checkBumpRuntimeDependencies checks the tools needed to update and build the
+// controllers, and to regenerate them unless --skip-generate is set.
+func checkBumpRuntimeDependencies(cmd *cobra.Command, args []string) error {
+	names := []string{"go"}
+	if !optBumpSkipGenerate {
+		names = append(names, "controller-gen")
+	}
+	return checkDependencies(cmd.Context(), names...)
+}
+
+func bumpRuntime(cmd *cobra.Command, args []string) error {
+	version := args[0]
+	if !semver.IsValid(version) {
+		return fmt.Errorf("%w: %q", semver.ErrInvalidVersion, version)
+	}
+
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+	codeGeneratorPath, err := coreRepositoryPath(cfg, codeGeneratorRepositoryName)
+	if err != nil {
+		return err
+	}
+	if !optBumpSkipGenerate {
+		_, err = os.Stat(filepath.Join(codeGeneratorPath, bump.GenerateScript))
+		if err != nil {
+			return fmt.Errorf("cannot generate controllers: %w, use --skip-generate to only update go.mod", err)
+		}
+	}
+	filters, err := repository.BuildFilters(optBumpFilterExpression)
+	if err != nil {
+		return err
+	}
+	filters = append(filters, repository.TypeFilter(repository.RepositoryTypeController))
+	repos, err := listRepositories(filters...)
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+	opts := []bump.Option{bump.WithOutput(out)}
+	if optBumpSkipGenerate {
+		opts = append(opts, bump.WithoutGenerate())
+	}
+	bumper := bump.NewBumper(codeGeneratorPath, opts...)
+
+	// controllers are bumped one after the other, the code generator doesn't
+	// support concurrent generations.
+	records := []*bumpRecord{}
+	for _, repo := range repos {
+		if !repo.Cloned() {
+			fmt.Printf("skipping %s: repository is not cloned, please run `ackdev ensure repos`\n", repo.Name)
+			continue
+		}
+		fmt.Printf("bumping %s to runtime %s\n", repo.Name, version)
+		records = append(records, &bumpRecord{
+			repo:   repo,
+			result: bumper.Bump(cmd.Context(), repo, version),
+		})
+	}
+
+	fmt.Println()
+	tablePrintBumpRecords(records)
+
+	failed := 0
+	for _, record := range records {
+		if record.result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("runtime bump failed in %d/%d repositories", failed, len(records))
+	}
+	return nil
+}
+
+func tablePrintBumpRecords(records []*bumpRecord) {
+	tw := newTable()
+	defer tw.Render()
+
+	tw.SetHeader(bumpTableHeaderColumns)
+
+	for _, record := range records {
+		errMessage := ""
+		if record.result.Err != nil {
+			errMessage = record.result.Err.Error()
+		}
+		tw.Append([]string{
+			record.repo.Name,
+			versionOrUnknown(record.result.PreviousVersion),
+			string(record.result.Status),
+			record.result.Branch,
+			errMessage,
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/cluster"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+)
+
+var (
+	optClusterName string
+)
+
+func init() {
+	clusterCmd.PersistentFlags().StringVar(&optClusterName, "name", "", "kind cluster name, overrides the configured name")
+
+	clusterCmd.AddCommand(clusterUpCmd)
+	clusterCmd.AddCommand(clusterDownCmd)
+	clusterCmd.AddCommand(clusterStatusCmd)
+}
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Args:  cobra.NoArgs,
+	Short: "Manages the local kind cluster",
+}
+
+// newClusterManager returns a cluster manager for the configured kind cluster.
+func newClusterManager(cfg *config.Config, out asyncexec.Output) *cluster.Manager {
+	clusterConfig := cfg.Cluster
+	if optClusterName != "" {
+		clusterConfig.Name = optClusterName
+	}
+	return cluster.NewManager(clusterConfig, stateDirectory(cfg), cluster.WithOutput(out))
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/cluster"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+)
+
+var (
+	clusterNodesTableHeaderColumns = []string{"Node", "Status", "Roles", "Version"}
+	clusterCRDsTableHeaderColumns  = []string{"CRD", "Versions"}
+)
+
+var clusterStatusCmd = &cobra.Command{
+	Use:     "status",
+	RunE:    printClusterStatus,
+	PreRunE: requireDependencies("kind"),
+	Args:    cobra.NoArgs,
+	Short:   "Display the local kind cluster nodes and installed ACK CRDs",
+}
+
+func printClusterStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+
+	clusterManager := newClusterManager(cfg, nil)
+	status, err := clusterManager.Status(cmd.Context())
+	if errors.Is(err, cluster.ErrClusterNotFound) {
+		fmt.Printf("cluster %s doesn't exist, run `ackdev cluster up` to create it\n", clusterManager.Name())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cluster:    %s\n", status.Name)
+	fmt.Printf("Kubeconfig: %s\n\n", status.KubeconfigPath)
+	tablePrintClusterNodes(status.Nodes)
+	fmt.Println()
+	if len(status.CRDs) == 0 {
+		fmt.Println("no ACK CRDs installed")
+		return nil
+	}
+	tablePrintClusterCRDs(status.CRDs)
+	return nil
+}
+
+func tablePrintClusterNodes(nodes []cluster.Node) {
+	tw := newTable()
+	defer tw.Render()
+	tw.SetHeader(clusterNodesTableHeaderColumns)
+
+	for _, node := range nodes {
+		status := "NotReady"
+		if node.Ready {
+			status = "Ready"
+		}
+		roles := "<none>"
+		if len(node.Roles) > 0 {
+			roles = strings.Join(node.Roles, ",")
+		}
+		tw.Append([]string{node.Name, status, roles, node.Version})
+	}
+}
+
+func tablePrintClusterCRDs(crds []cluster.CRD) {
+	tw := newTable()
+	defer tw.Render()
+	tw.SetHeader(clusterCRDsTableHeaderColumns)
+
+	for _, crd := range crds {
+		tw.Append([]string{crd.Name, strings.Join(crd.Versions, ",")})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+)
+
+var clusterUpCmd = &cobra.Command{
+	Use:     "up",
+	RunE:    clusterUp,
+	PreRunE: requireDependencies("kind", "kubectl"),
+	Args:    cobra.NoArgs,
+	Short:   "Create the local kind cluster and wait for it to be ready",
+	Example: "ackdev cluster up --name ack-test",
+}
+
+func clusterUp(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+
+	clusterManager := newClusterManager(cfg, out)
+	err = clusterManager.Up(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("cluster %s is ready, to use it run:\n", clusterManager.Name())
+	fmt.Printf("export KUBECONFIG=%s\n", clusterManager.KubeconfigPath())
+	return nil
+}
@@ -14,24 +14,38 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go/build"
 	"os"
 	"path/filepath"
+	"strings"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/deps"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
 )
 
 const (
-	ackdevConfigFileName = ".ackdev.yaml"
+	ackdevConfigFileName     = ".ackdev.yaml"
+	ackdevStateDirectoryName = ".ackdev"
+
+	codeGeneratorRepositoryName = "code-generator"
+	testInfraRepositoryName     = "test-infra"
 )
 
 var (
-	homeDirectory        string
-	defaultConfigPath    string
-	goPath               = build.Default.GOPATH
-	defaultRootDirectory = filepath.Join(goPath, "src/github.com/aws-controllers-k8s")
+	homeDirectory         string
+	defaultConfigPath     string
+	defaultStateDirectory string
+	goPath                = build.Default.GOPATH
+	defaultRootDirectory  = filepath.Join(goPath, "src/github.com/aws-controllers-k8s")
 )
 
 func init() {
@@ -43,6 +57,16 @@ func init() {
 	}
 	homeDirectory = hd
 	defaultConfigPath = filepath.Join(homeDirectory, ackdevConfigFileName)
+	defaultStateDirectory = filepath.Join(homeDirectory, ackdevStateDirectoryName)
+}
+
+// stateDirectory returns the directory where ackdev stores the files it
+// generates.
+func stateDirectory(cfg *config.Config) string {
+	if cfg.StateDirectory != "" {
+		return cfg.StateDirectory
+	}
+	return defaultStateDirectory
 }
 
 func newTable() *tablewriter.Table {
@@ -62,3 +86,108 @@ func newTable() *tablewriter.Table {
 	table.SetNoWhiteSpace(true)
 	return table
 }
+
+// newCommandOutput returns an output writing the lines of the commands run by
+// ackdev to stdout and stderr, without label.
+func newCommandOutput() *asyncexec.Source {
+	// sources without options never fail to be created
+	source, _ := asyncexec.NewMultiplexer(os.Stdout, os.Stderr).NewSource("")
+	return source
+}
+
+// loadControllerRepository loads the configuration and the local repository
+// of a service controller.
+func loadControllerRepository(service string) (*config.Config, *repository.Repository, error) {
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	repoManager, err := repository.NewManager(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := repoManager.LoadRepository(strings.ToLower(service), repository.RepositoryTypeController)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load %s controller repository: %w", service, err)
+	}
+	if !repo.Cloned() {
+		return nil, nil, fmt.Errorf("%s is not cloned, please run `ackdev ensure repos`", repo.FullPath)
+	}
+	return cfg, repo, nil
+}
+
+// runProfile resolves a run profile for a controller repository. An empty name
+// selects the default profile.
+func runProfile(cfg *config.Config, name string, repo *repository.Repository) (*config.Profile, error) {
+	return cfg.RunConfig.Profile(name, strings.TrimSuffix(repo.Name, "-controller"))
+}
+
+// coreRepositoryPath returns the path of the local checkout of a core
+// repository.
+func coreRepositoryPath(cfg *config.Config, name string) (string, error) {
+	repoManager, err := repository.NewManager(cfg)
+	if err != nil {
+		return "", err
+	}
+	repo, err := repoManager.LoadRepository(name, repository.RepositoryTypeCore)
+	if err != nil {
+		return "", fmt.Errorf("cannot load %s repository: %w", name, err)
+	}
+	return repo.FullPath, nil
+}
+
+// requireDependencies returns a cobra PreRunE function checking that the given
+// development tools are installed before the command runs.
+func requireDependencies(names ...string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return checkDependencies(cmd.Context(), names...)
+	}
+}
+
+// checkDependencies returns an error if one of the given development tools is
+// missing or older than its minimum version.
+func checkDependencies(ctx context.Context, names ...string) error {
+	tools, err := developmentTools()
+	if err != nil {
+		return err
+	}
+	err = deps.Check(ctx, tools, names...)
+	var missingErr *deps.MissingError
+	if errors.As(err, &missingErr) {
+		return fmt.Errorf("%w, run `ackdev list deps` to check the development tools", err)
+	}
+	return err
+}
+
+// developmentTools returns the built-in development tools merged with the
+// dependencies declared in the configuration.
+func developmentTools() ([]deps.Dependency, error) {
+	// dependencies can be checked before the configuration file is created
+	var customDependencies []config.DependencyConfig
+	cfg, err := config.Load(ackConfigPath)
+	if err == nil {
+		customDependencies = cfg.Dependencies
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return deps.Merge(deps.DevelopmentTools, newDependencies(customDependencies)), nil
+}
+
+// newDependencies returns the dependencies declared in the configuration.
+func newDependencies(dependencyConfigs []config.DependencyConfig) []deps.Dependency {
+	dependencies := make([]deps.Dependency, 0, len(dependencyConfigs))
+	for _, dc := range dependencyConfigs {
+		dependency := deps.Dependency{
+			BinaryName:     dc.Name,
+			GetVersionArgs: dc.VersionArgs,
+			VersionRegex:   dc.VersionRegex,
+			MinVersion:     dc.MinVersion,
+		}
+		if dc.Optional {
+			dependency.Requirement = deps.Optional
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
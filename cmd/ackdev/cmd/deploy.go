@@ -0,0 +1,155 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/deploy"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/image"
+)
+
+var (
+	optDeployImage     string
+	optDeployLoadImage bool
+	optDeployNamespace string
+)
+
+func init() {
+	deployCmd.PersistentFlags().StringVar(&optDeployImage, "image", "", "controller image, defaults to the last image built by ackdev build image, or to the image of the Helm chart")
+	deployCmd.PersistentFlags().BoolVar(&optDeployLoadImage, "load", false, "load the image from the local docker daemon into the kind cluster")
+	deployCmd.PersistentFlags().StringVarP(&optDeployNamespace, "namespace", "n", deploy.DefaultNamespace, "namespace of the controller")
+	deployCmd.PersistentFlags().StringVar(&optClusterName, "cluster", "", "kind cluster name, overrides the configured name")
+
+	undeployCmd.PersistentFlags().StringVarP(&optDeployNamespace, "namespace", "n", deploy.DefaultNamespace, "namespace of the controller")
+	undeployCmd.PersistentFlags().StringVar(&optClusterName, "cluster", "", "kind cluster name, overrides the configured name")
+}
+
+var deployCmd = &cobra.Command{
+	Use:     "deploy <service>",
+	RunE:    deployController,
+	PreRunE: requireDependencies("kind", "kubectl", "helm"),
+	Args:    cobra.ExactArgs(1),
+	Short:   "Install a service controller CRDs and Helm chart into the local cluster",
+	Example: "ackdev deploy s3 --image ack-s3-controller:dev --load",
+}
+
+var undeployCmd = &cobra.Command{
+	Use:     "undeploy <service>",
+	RunE:    undeployController,
+	PreRunE: requireDependencies("kind", "kubectl", "helm"),
+	Args:    cobra.ExactArgs(1),
+	Short:   "Uninstall a service controller and its CRDs from the local cluster",
+}
+
+func deployController(cmd *cobra.Command, args []string) error {
+	if optDeployLoadImage && optDeployImage == "" {
+		return fmt.Errorf("--load requires an --image")
+	}
+
+	cfg, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+
+	profile, err := runProfile(cfg, "", repo)
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+
+	clusterManager := newClusterManager(cfg, out)
+	opts := deploy.Options{
+		Namespace: optDeployNamespace,
+		Image:     optDeployImage,
+		LoadImage: optDeployLoadImage,
+		Flags:     profile.Flags,
+	}
+
+	// Use the last image built by `ackdev build image`, loading it into the
+	// cluster if needed.
+	imageStore := image.NewStore(stateDirectory(cfg))
+	var record *image.Record
+	if opts.Image == "" {
+		record, err = imageStore.Get(repo.Name)
+		if err != nil {
+			return err
+		}
+	}
+	ctx := cmd.Context()
+	var clusterID string
+	if record != nil {
+		// the cluster ID changes when the cluster is recreated, unlike its name
+		err = clusterManager.EnsureRunning(ctx)
+		if err != nil {
+			return err
+		}
+		clusterID, err = clusterManager.ID(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("using image %s\n", record.Image)
+		opts.Image = record.Image
+		opts.LoadImage = !record.LoadedInto(clusterID)
+	}
+
+	if opts.LoadImage {
+		// kind loads the images from the local docker daemon
+		err = checkDependencies(ctx, "docker")
+		if err != nil {
+			return err
+		}
+	}
+
+	deployer := deploy.NewDeployer(clusterManager, deploy.WithOutput(out))
+	err = deployer.Deploy(ctx, repo, opts)
+	if err != nil {
+		return err
+	}
+
+	if record != nil && opts.LoadImage {
+		record.ClusterIDs = append(record.ClusterIDs, clusterID)
+		if err := imageStore.Put(repo.Name, record); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s deployed in namespace %s\n", repo.Name, optDeployNamespace)
+	return nil
+}
+
+func undeployController(cmd *cobra.Command, args []string) error {
+	cfg, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+
+	deployer := deploy.NewDeployer(newClusterManager(cfg, out), deploy.WithOutput(out))
+	err = deployer.Undeploy(cmd.Context(), repo, deploy.Options{
+		Namespace: optDeployNamespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s undeployed\n", repo.Name)
+	return nil
+}
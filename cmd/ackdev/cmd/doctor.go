@@ -0,0 +1,139 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/doctor"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/github"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/gomod"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	doctorTableHeaderColumns = []string{"Check", "Status", "Details"}
+
+	doctorStatusColors = map[doctor.Status]int{
+		doctor.StatusPass: tablewriter.FgGreenColor,
+		doctor.StatusWarn: tablewriter.FgYellowColor,
+		doctor.StatusFail: tablewriter.FgRedColor,
+	}
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	RunE:  runDoctor,
+	Args:  cobra.NoArgs,
+	Short: "Check the development environment and suggest fixes",
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	d := doctor.New()
+
+	cfg, configResult := d.CheckConfig(ackConfigPath)
+	rootDirectory := defaultRootDirectory
+	if cfg != nil && cfg.RootDirectory != "" {
+		rootDirectory = cfg.RootDirectory
+	}
+
+	results := []*doctor.Result{
+		d.CheckGoVersion(ctx, controllerGoDirectives(cfg)),
+		d.CheckGoBinPath(ctx),
+		d.CheckGoProxy(ctx),
+		d.CheckDocker(ctx),
+		d.CheckDiskSpace(rootDirectory),
+		configResult,
+		d.CheckGitIdentity(ctx),
+	}
+	// the token is only known when the configuration is valid
+	if cfg != nil {
+		var client doctor.TokenScoper
+		if cfg.Github.Token != "" {
+			client = github.NewClient(cfg.Github.Token)
+		}
+		results = append(results, d.CheckGithubToken(ctx, client))
+	}
+
+	tablePrintDoctorResults(results)
+
+	failed := 0
+	hints := []string{}
+	for _, result := range results {
+		if result.Status == doctor.StatusFail {
+			failed++
+		}
+		if result.Status != doctor.StatusPass && result.Hint != "" {
+			hints = append(hints, fmt.Sprintf("  %s: %s", result.Check, result.Hint))
+		}
+	}
+	if len(hints) > 0 {
+		fmt.Println("\nto fix the issues:")
+		for _, hint := range hints {
+			fmt.Println(hint)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// controllerGoDirectives returns the go directives of the cloned controllers
+// go.mod files, indexed by repository name.
+func controllerGoDirectives(cfg *config.Config) map[string]string {
+	directives := map[string]string{}
+	if cfg == nil {
+		return directives
+	}
+	repoManager, err := repository.NewManager(cfg)
+	if err != nil {
+		return directives
+	}
+	// repositories that can't be loaded are reported by the other commands
+	_ = repoManager.LoadAll()
+	for _, repo := range repoManager.List() {
+		if repo.Type != repository.RepositoryTypeController || !repo.Cloned() {
+			continue
+		}
+		goMod, err := gomod.ReadFile(repo.FullPath)
+		if err != nil || goMod.Go == "" {
+			continue
+		}
+		directives[repo.Name] = goMod.Go
+	}
+	return directives
+}
+
+func tablePrintDoctorResults(results []*doctor.Result) {
+	tw := newTable()
+	defer tw.Render()
+
+	tw.SetHeader(doctorTableHeaderColumns)
+
+	highlight := isInteractive()
+	for _, result := range results {
+		row := []string{result.Check, string(result.Status), result.Message}
+		if highlight {
+			tw.Rich(row, []tablewriter.Colors{{}, {doctorStatusColors[result.Status]}, {}})
+			continue
+		}
+		tw.Append(row)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	execTableHeaderColumns = []string{"Name", "Exit Code", "Duration", "Error"}
+
+	optExecFilterExpression string
+	optExecConcurrency      int
+	optExecLogDirectory     string
+	optExecNoColor          bool
+)
+
+func init() {
+	execCmd.PersistentFlags().StringVarP(&optExecFilterExpression, "filter", "f", "", "filter expression")
+	execCmd.PersistentFlags().IntVarP(&optExecConcurrency, "concurrency", "j", 4, "maximum number of commands running at the same time")
+	execCmd.PersistentFlags().StringVar(&optExecLogDirectory, "log-dir", "", "directory where the output of each command is saved, in <repository>.log files")
+	execCmd.PersistentFlags().BoolVar(&optExecNoColor, "no-color", false, "do not colourise the repository names")
+}
+
+var execCmd = &cobra.Command{
+	Use:     "exec -- <command> [args...]",
+	RunE:    execInRepositories,
+	Args:    cobra.MinimumNArgs(1),
+	Short:   "Execute a command in multiple repositories",
+	Example: "ackdev exec -f type=controller -- go test ./...",
+}
+
+// execResult is the outcome of a command executed in a repository.
+type execResult struct {
+	repo     *repository.Repository
+	exitCode int
+	duration time.Duration
+	err      error
+}
+
+func execInRepositories(cmd *cobra.Command, args []string) error {
+	if optExecConcurrency < 1 {
+		return fmt.Errorf("concurrency must be greater than 0")
+	}
+
+	filters, err := repository.BuildFilters(optExecFilterExpression)
+	if err != nil {
+		return err
+	}
+	repos, err := listRepositories(filters...)
+	if err != nil {
+		return err
+	}
+
+	// Only run the command in repositories that exist locally
+	cloned := []*repository.Repository{}
+	for _, repo := range repos {
+		if !repo.Cloned() {
+			fmt.Printf("skipping %s: repository is not cloned, please run `ackdev ensure repos`\n", repo.Name)
+			continue
+		}
+		cloned = append(cloned, repo)
+	}
+
+	if optExecLogDirectory != "" {
+		err = os.MkdirAll(optExecLogDirectory, os.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+
+	labelWidth := 0
+	for _, repo := range cloned {
+		if len(repo.Name) > labelWidth {
+			labelWidth = len(repo.Name)
+		}
+	}
+	out := asyncexec.NewMultiplexer(
+		os.Stdout, os.Stderr,
+		asyncexec.WithLabelWidth(labelWidth),
+		asyncexec.WithColor(!optExecNoColor && isInteractive()),
+	)
+	results := make([]*execResult, len(cloned))
+	sem := make(chan struct{}, optExecConcurrency)
+	var wg sync.WaitGroup
+	for i, repo := range cloned {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo *repository.Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = execInRepository(cmd.Context(), repo, args, out)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	tablePrintExecResults(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d/%d repositories", failed, len(results))
+	}
+	return nil
+}
+
+// execInRepository runs a command in the repository directory, streaming its
+// output through the given multiplexer.
+func execInRepository(ctx context.Context, repo *repository.Repository, args []string, out *asyncexec.Multiplexer) *execResult {
+	result := &execResult{repo: repo, exitCode: -1}
+
+	opts := []asyncexec.SourceOption{}
+	if optExecLogDirectory != "" {
+		opts = append(opts, asyncexec.WithLogFile(filepath.Join(optExecLogDirectory, repo.Name+".log")))
+	}
+	source, err := out.NewSource(repo.Name, opts...)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer source.Close()
+
+	c := exec.Command(args[0], args[1:]...)
+	c.Dir = repo.FullPath
+	res, err := asyncexec.New(c, asyncexec.WithContext(ctx), asyncexec.WithOutput(source)).Run()
+	if res != nil {
+		result.exitCode = res.ExitCode
+		result.duration = res.Duration
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			err = fmt.Errorf("exited with code %d", result.exitCode)
+		}
+		result.err = err
+	}
+	return result
+}
+
+func tablePrintExecResults(results []*execResult) {
+	tw := newTable()
+	defer tw.Render()
+
+	tw.SetHeader(execTableHeaderColumns)
+
+	for _, result := range results {
+		errMessage := ""
+		if result.err != nil {
+			errMessage = result.err.Error()
+		}
+		tw.Append([]string{
+			result.repo.Name,
+			strconv.Itoa(result.exitCode),
+			result.duration.Round(time.Millisecond).String(),
+			errMessage,
+		})
+	}
+}
@@ -22,6 +22,7 @@ var (
 func init() {
 	listCmd.AddCommand(listDependenciesCmd)
 	listCmd.AddCommand(listRepositoriesCmd)
+	listCmd.AddCommand(listVersionsCmd)
 	listCmd.AddCommand(getConfigCmd)
 
 	getConfigCmd.PersistentFlags().StringVarP(&optListOutputFormat, "output", "o", "yaml", "output format (json|yaml)")
@@ -14,6 +14,11 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/aws-controllers-k8s/dev-tools/pkg/deps"
@@ -23,11 +28,13 @@ var (
 	listDepsTableHeaderColumns = []string{"Name", "Status"}
 	optDepsListShowPath        bool
 	optDepsListShowVersion     bool
+	optDepsListTimeout         time.Duration
 )
 
 func init() {
 	listDependenciesCmd.PersistentFlags().BoolVar(&optDepsListShowPath, "show-path", true, "display binary path")
 	listDependenciesCmd.PersistentFlags().BoolVar(&optDepsListShowVersion, "show-version", true, "display binary version")
+	listDependenciesCmd.PersistentFlags().DurationVar(&optDepsListTimeout, "timeout", deps.DefaultTimeout, "timeout of each binary version command")
 }
 
 var listDependenciesCmd = &cobra.Command{
@@ -41,15 +48,23 @@ type depRecord struct {
 	Version string
 	Path    string
 	Status  string
+	Err     error
 }
 
 func printDependencies(cmd *cobra.Command, args []string) error {
-	dependencies, err := listDependencies()
+	tools, err := developmentTools()
 	if err != nil {
 		return err
 	}
 
+	dependencies := listDependencies(cmd.Context(), tools, optDepsListTimeout)
 	tablePrintDependencies(dependencies)
+
+	for _, dependency := range dependencies {
+		if dependency.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", dependency.Name, dependency.Err)
+		}
+	}
 	return nil
 }
 
@@ -84,33 +99,25 @@ func tablePrintDependencies(dependencies []*depRecord) error {
 	return nil
 }
 
-// listDependencies returns the list of ACK development dependencies
-// along with their versions and binary paths.
-func listDependencies() ([]*depRecord, error) {
-	list := make([]*depRecord, 0, len(deps.DevelopmentTools))
-	for _, tool := range deps.DevelopmentTools {
-		status := ""
-		path, err := tool.BinPath()
-		if err != nil {
-			status = "NOT FOUND"
-		} else {
-			status = "OK"
-		}
-
-		version := "-"
-		if status == "OK" {
-			v, err := tool.Version()
-			if err != nil && err != deps.ErrorVersionNotFound {
-				return nil, err
-			}
-			version = v
+// listDependencies returns the given ACK development dependencies along with
+// their versions and binary paths. The dependencies are probed concurrently,
+// and version commands that don't complete within the timeout are reported
+// with the TIMEOUT status.
+func listDependencies(ctx context.Context, dependencies []deps.Dependency, timeout time.Duration) []*depRecord {
+	results := deps.Probe(ctx, dependencies, timeout)
+	list := make([]*depRecord, 0, len(results))
+	for _, result := range results {
+		version := result.Version
+		if result.Status != deps.StatusOK && version == "" {
+			version = "-"
 		}
 		list = append(list, &depRecord{
-			Name:    tool.BinaryName,
+			Name:    result.Dependency.BinaryName,
 			Version: version,
-			Path:    path,
-			Status:  status,
+			Path:    result.Path,
+			Status:  result.Status,
+			Err:     result.Err,
 		})
 	}
-	return list, nil
+	return list
 }
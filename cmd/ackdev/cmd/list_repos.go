@@ -55,6 +55,19 @@ func printRepositories(cmd *cobra.Command, args []string) error {
 }
 
 func listRepositories(filters ...repository.Filter) ([]*repository.Repository, error) {
+	repoManager, err := loadRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	// List repositories
+	//TODO(hilalymh) add sort-by flag/option
+	return repoManager.List(filters...), nil
+}
+
+// loadRepositoryManager returns a repository manager with all the configured
+// repositories loaded.
+func loadRepositoryManager() (*repository.Manager, error) {
 	cfg, err := config.Load(ackConfigPath)
 	if err != nil {
 		return nil, err
@@ -69,14 +82,7 @@ func listRepositories(filters ...repository.Filter) ([]*repository.Repository, e
 	if err != nil {
 		return nil, err
 	}
-
-	// List repositories
-	//TODO(hilalymh) add sort-by flag/option
-	repos := repoManager.List(filters...)
-	if err != nil {
-		return nil, err
-	}
-	return repos, nil
+	return repoManager, nil
 }
 
 func tablePrintRepositories(repos []*repository.Repository) {
@@ -0,0 +1,154 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/controller"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+const (
+	runtimeRepositoryName = "runtime"
+	unknownVersion        = "-"
+)
+
+var (
+	listVersionsTableHeaderColumns = []string{"Name", "Runtime", "Code Generator", "AWS SDK Go", "Latest Release", "Status"}
+
+	optListVersionsFilterExpression string
+)
+
+func init() {
+	listVersionsCmd.PersistentFlags().StringVarP(&optListVersionsFilterExpression, "filter", "f", "", "filter expression")
+}
+
+var listVersionsCmd = &cobra.Command{
+	Use:     "versions",
+	Aliases: []string{"version"},
+	RunE:    printVersions,
+	Args:    cobra.NoArgs,
+	Short:   "Display the runtime, code-generator and aws-sdk-go versions of the controllers",
+}
+
+// versionsRecord contains the versions of a controller local checkout.
+type versionsRecord struct {
+	Name          string
+	Versions      *controller.Versions
+	LatestRelease string
+	// Behind is true if the controller requires a runtime version older than
+	// the latest runtime release.
+	Behind bool
+	Err    error
+}
+
+func printVersions(cmd *cobra.Command, args []string) error {
+	filters, err := repository.BuildFilters(optListVersionsFilterExpression)
+	if err != nil {
+		return err
+	}
+	repoManager, err := loadRepositoryManager()
+	if err != nil {
+		return err
+	}
+	runtimeRelease, err := latestRuntimeRelease(repoManager)
+	if err != nil {
+		return err
+	}
+
+	records := []*versionsRecord{}
+	for _, repo := range repoManager.List(filters...) {
+		if repo.Type != repository.RepositoryTypeController || !repo.Cloned() {
+			continue
+		}
+		records = append(records, readVersionsRecord(repo, runtimeRelease))
+	}
+
+	if runtimeRelease != "" {
+		fmt.Printf("latest runtime release: %s\n\n", runtimeRelease)
+	}
+	tablePrintVersions(records, runtimeRelease)
+	return nil
+}
+
+// latestRuntimeRelease returns the latest release tag of the local runtime
+// checkout, or an empty string if it isn't cloned.
+func latestRuntimeRelease(repoManager *repository.Manager) (string, error) {
+	repos := repoManager.List(repository.NameFilter(runtimeRepositoryName))
+	if len(repos) == 0 || !repos[0].Cloned() {
+		return "", nil
+	}
+	return repos[0].LatestReleaseTag()
+}
+
+func readVersionsRecord(repo *repository.Repository, runtimeRelease string) *versionsRecord {
+	record := &versionsRecord{Name: repo.Name}
+	record.Versions, record.Err = controller.ReadVersions(repo.FullPath)
+	if record.Err != nil {
+		return record
+	}
+	record.LatestRelease, record.Err = repo.LatestReleaseTag()
+	if runtimeRelease != "" && semver.IsValid(record.Versions.Runtime) {
+		record.Behind = semver.Compare(record.Versions.Runtime, runtimeRelease) < 0
+	}
+	return record
+}
+
+func tablePrintVersions(records []*versionsRecord, runtimeRelease string) {
+	tw := newTable()
+	defer tw.Render()
+
+	tw.SetHeader(listVersionsTableHeaderColumns)
+
+	highlight := isInteractive()
+	for _, record := range records {
+		if record.Err != nil {
+			tw.Append([]string{record.Name, unknownVersion, unknownVersion, unknownVersion, unknownVersion, "ERROR: " + record.Err.Error()})
+			continue
+		}
+		status := "OK"
+		if record.Behind {
+			status = "BEHIND " + runtimeRelease
+		}
+		row := []string{
+			record.Name,
+			versionOrUnknown(record.Versions.Runtime),
+			versionOrUnknown(record.Versions.CodeGenerator),
+			versionOrUnknown(record.Versions.AWSSDKGo),
+			versionOrUnknown(record.LatestRelease),
+			status,
+		}
+		if highlight && record.Behind {
+			colors := make([]tablewriter.Colors, len(row))
+			for i := range colors {
+				colors[i] = tablewriter.Colors{tablewriter.FgYellowColor}
+			}
+			tw.Rich(row, colors)
+			continue
+		}
+		tw.Append(row)
+	}
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return unknownVersion
+	}
+	return version
+}
@@ -0,0 +1,98 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/release"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	optReleaseChangelogFile string
+)
+
+func init() {
+	releasePrepareCmd.PersistentFlags().StringVar(&optReleaseChangelogFile, "changelog-file", "", "file where the changelog is written, in addition to stdout")
+}
+
+var releasePrepareCmd = &cobra.Command{
+	Use:     "prepare <service> <version>",
+	RunE:    releasePrepare,
+	Args:    cobra.ExactArgs(2),
+	Short:   "Bump a controller chart and image versions and generate its changelog",
+	Example: "ackdev release prepare s3 v0.1.0 --changelog-file /tmp/s3-v0.1.0.md",
+}
+
+func releasePrepare(cmd *cobra.Command, args []string) error {
+	version := args[1]
+	_, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+
+	dirty, err := repo.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("cannot prepare release: %s: %w", repo.Name, repository.ErrUncommittedChanges)
+	}
+	// releases are tagged on the default branch
+	if repo.GitHead != repo.DefaultBranch {
+		return fmt.Errorf("cannot prepare release: %s is on %s, please checkout the default branch %s", repo.Name, repo.GitHead, repo.DefaultBranch)
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return err
+	}
+	previous, err := release.ValidateVersion(version, tags)
+	if err != nil {
+		return err
+	}
+
+	updated, err := release.UpdateFiles(repo.FullPath, version)
+	if err != nil {
+		return err
+	}
+	for _, file := range updated {
+		fmt.Printf("updated %s\n", file)
+	}
+
+	commits, err := release.Log(cmd.Context(), "git", repo.FullPath, previous)
+	if err != nil {
+		return err
+	}
+	changelog := (&release.Changelog{
+		Version:         version,
+		PreviousVersion: previous,
+		Commits:         commits,
+	}).Markdown()
+	fmt.Printf("\n%s\n", changelog)
+
+	if optReleaseChangelogFile != "" {
+		err = ioutil.WriteFile(optReleaseChangelogFile, []byte(changelog), 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("changelog written to %s\n", optReleaseChangelogFile)
+	}
+	fmt.Printf("review and commit the changes, then tag the release with `git tag %s`\n", version)
+	return nil
+}
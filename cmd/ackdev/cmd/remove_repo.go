@@ -0,0 +1,136 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/github"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	optRemoveRepoDeleteLocal bool
+	optRemoveRepoDeleteFork  bool
+)
+
+func init() {
+	removeRepositoryCmd.PersistentFlags().BoolVar(&optRemoveRepoDeleteLocal, "delete-local", false, "delete the local clone, if it has no uncommitted changes, stashes or unpushed branches")
+	removeRepositoryCmd.PersistentFlags().BoolVar(&optRemoveRepoDeleteFork, "delete-fork", false, "delete the Github fork, after confirmation")
+}
+
+var removeRepositoryCmd = &cobra.Command{
+	Use:     "repository <service> ...",
+	Aliases: []string{"repo", "repos", "repository", "repositories"},
+	RunE:    removeRepository,
+	Args:    cobra.MinimumNArgs(1),
+	Example: "ackdev remove repo s3 --delete-local",
+}
+
+func removeRepository(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+	if optRemoveRepoDeleteFork {
+		if cfg.Github.Token == "" || cfg.Github.Username == "" {
+			return fmt.Errorf("github username and token are required to delete forks, please run `ackdev edit config`")
+		}
+		if !isInteractive() {
+			return fmt.Errorf("deleting forks requires a confirmation, please run ackdev from a terminal")
+		}
+	}
+
+	repoManager, err := repository.NewManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	for _, service := range args {
+		service = strings.ToLower(service)
+
+		if config.FindRepository(cfg.Repositories.Services, service) == nil {
+			fmt.Printf("controller repository %s is not part of the configuration\n", service)
+			continue
+		}
+
+		repo, err := repoManager.LoadRepository(service, repository.RepositoryTypeController)
+		if err != nil {
+			return err
+		}
+
+		// Verify the local clone can be deleted before making any change, so that
+		// the repository is either fully removed or left untouched. When the fork
+		// is deleted too, the branches pushed to origin are lost with it.
+		if optRemoveRepoDeleteLocal {
+			check := repo.CheckSafeToDelete
+			if optRemoveRepoDeleteFork {
+				check = repo.CheckSafeToDeleteWithFork
+			}
+			if err := check(); err != nil {
+				return fmt.Errorf("cannot delete %s: %w", repo.FullPath, err)
+			}
+		}
+
+		deleteFork := false
+		if optRemoveRepoDeleteFork {
+			deleteFork, err = promptConfirm(fmt.Sprintf(
+				"Delete the fork %s/%s? Branches that only exist on the fork will be lost",
+				cfg.Github.Username, repo.ExpectedForkName,
+			), false)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The local clone is deleted before the fork, since it is the step that
+		// can still fail, so that the fork is never deleted alone.
+		if optRemoveRepoDeleteLocal && repo.Cloned() {
+			deleteLocal := repoManager.DeleteLocal
+			if deleteFork {
+				deleteLocal = repoManager.DeleteLocalWithFork
+			}
+			if err := deleteLocal(repo); err != nil {
+				return err
+			}
+			fmt.Printf("deleted %s\n", repo.FullPath)
+		}
+
+		if deleteFork {
+			err := repoManager.DeleteFork(ctx, repo)
+			switch {
+			case errors.Is(err, github.ErrForkNotFound):
+				fmt.Printf("fork %s/%s not found, nothing to delete\n", cfg.Github.Username, repo.ExpectedForkName)
+			case err != nil:
+				return err
+			default:
+				fmt.Printf("deleted fork %s/%s\n", cfg.Github.Username, repo.ExpectedForkName)
+			}
+		}
+
+		cfg.Repositories.Services = config.RemoveRepository(cfg.Repositories.Services, service)
+		if err := config.Save(cfg, ackConfigPath); err != nil {
+			return err
+		}
+		fmt.Printf("removed controller repository %s\n", service)
+	}
+
+	return nil
+}
@@ -30,9 +30,21 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(editCmd)
 	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(ensureCmd)
+	rootCmd.AddCommand(adoptCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(clusterCmd)
+	rootCmd.AddCommand(deployCmd)
+	rootCmd.AddCommand(undeployCmd)
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(bumpCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 var rootCmd = &cobra.Command{
@@ -43,7 +55,10 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, cancel := signalContext()
+	err := rootCmd.ExecuteContext(ctx)
+	cancel()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,46 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/controller"
+)
+
+var (
+	optRunEmulator  bool
+	optRunProfile   string
+	optRunWatch     bool
+	optRunDebug     bool
+	optRunDebugPort int
+)
+
+func init() {
+	runCmd.PersistentFlags().StringVarP(&optRunProfile, "profile", "p", config.DefaultRunProfile, "run profile")
+	runCmd.PersistentFlags().BoolVar(&optRunEmulator, "emulator", false, "start the local AWS emulator of the run configuration and point the controller to it")
+	runCmd.PersistentFlags().BoolVarP(&optRunWatch, "watch", "w", false, "rebuild and restart the controller when its Go files change")
+	runCmd.PersistentFlags().BoolVar(&optRunDebug, "debug", false, "build the controller without optimizations and run it under a headless dlv server")
+	runCmd.PersistentFlags().IntVar(&optRunDebugPort, "debug-port", controller.DefaultDebugPort, "port of the dlv server")
+	runCmd.PersistentFlags().StringVar(&optClusterName, "cluster", "", "kind cluster name, overrides the configured name")
+
+	runCmd.AddCommand(runControllerCmd)
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Args:  cobra.NoArgs,
+	Short: "Runs service controllers locally",
+}
@@ -0,0 +1,235 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/controller"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/deploy"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/emulator"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+const (
+	controllerLabel = "controller"
+	emulatorLabel   = "emulator"
+)
+
+var (
+	errEmulatorExited = errors.New("the emulator exited, stopping the controller")
+)
+
+var runControllerCmd = &cobra.Command{
+	Use:     "controller <service>",
+	RunE:    runController,
+	Args:    cobra.ExactArgs(1),
+	Short:   "Build and run a service controller locally, against the local cluster",
+	Example: "ackdev run controller s3 --emulator",
+}
+
+// controllerBinaryPath returns the path of the controller binaries built by
+// ackdev run.
+func controllerBinaryPath(cfg *config.Config, repo *repository.Repository) string {
+	return filepath.Join(stateDirectory(cfg), "bin", repo.Name)
+}
+
+func runController(cmd *cobra.Command, args []string) error {
+	cfg, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+	if optRunEmulator && cfg.RunConfig.Emulator == nil {
+		return fmt.Errorf("no emulator configured, please add a run.emulator section to the configuration")
+	}
+	profile, err := runProfile(cfg, optRunProfile, repo)
+	if err != nil {
+		return err
+	}
+	requiredDependencies := []string{"go"}
+	if profile.Kubeconfig == "" {
+		requiredDependencies = append(requiredDependencies, "kind", "kubectl")
+	}
+	if optRunDebug {
+		requiredDependencies = append(requiredDependencies, "dlv")
+	}
+	if optRunEmulator && cfg.RunConfig.Emulator.Image != "" {
+		// container emulators are run with docker
+		requiredDependencies = append(requiredDependencies, "docker")
+	}
+	err = checkDependencies(cmd.Context(), requiredDependencies...)
+	if err != nil {
+		return err
+	}
+
+	out := newCommandOutput()
+	defer out.Close()
+	mux := asyncexec.NewMultiplexer(
+		os.Stdout, os.Stderr,
+		asyncexec.WithColor(isInteractive()),
+		asyncexec.WithLabelWidth(len(controllerLabel)),
+	)
+	ctx := cmd.Context()
+
+	// Profiles without kubeconfig run the controller against the local
+	// cluster, which is created if needed and gets the controller CRDs.
+	kubeconfig := profile.Kubeconfig
+	if kubeconfig == "" {
+		clusterManager := newClusterManager(cfg, out)
+		err = clusterManager.Up(ctx)
+		if err != nil {
+			return err
+		}
+		err = deploy.NewDeployer(clusterManager, deploy.WithOutput(out)).ApplyCRDs(ctx, repo)
+		if err != nil {
+			return err
+		}
+		kubeconfig = clusterManager.KubeconfigPath()
+	} else {
+		fmt.Printf("using kubeconfig %s, the controller CRDs must be installed in the cluster\n", kubeconfig)
+	}
+
+	flags := profile.Flags
+	env := append(profile.EnvList(), "KUBECONFIG="+kubeconfig)
+	var emulatorExited <-chan struct{}
+	if optRunEmulator {
+		emulatorOut, err := mux.NewSource(emulatorLabel)
+		if err != nil {
+			return err
+		}
+		defer emulatorOut.Close()
+
+		em := emulator.New(*cfg.RunConfig.Emulator, emulator.WithOutput(emulatorOut))
+		fmt.Printf("starting emulator on %s\n", em.Endpoint())
+		err = em.Start(ctx)
+		if err != nil {
+			return err
+		}
+		defer em.Stop(context.Background())
+		emulatorExited = em.Exited()
+
+		flags = em.Flags(flags)
+		env = append(env, emulator.Env()...)
+	}
+
+	controllerOut, err := mux.NewSource(controllerLabel)
+	if err != nil {
+		return err
+	}
+	defer controllerOut.Close()
+	runner := controller.NewRunner(controller.WithOutput(controllerOut), controller.WithEnv(env...))
+
+	launcher := &controllerLauncher{
+		runner: runner,
+		repo:   repo,
+		flags:  flags,
+	}
+	if optRunDebug {
+		launcher.debugPort = optRunDebugPort
+	}
+	binPath := controllerBinaryPath(cfg, repo)
+	if optRunWatch {
+		return watchController(ctx, launcher, binPath, emulatorExited)
+	}
+
+	fmt.Printf("building %s\n", repo.Name)
+	err = launcher.build(ctx, binPath)
+	if err != nil {
+		return err
+	}
+	process, err := launcher.start(ctx, binPath)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-process.exited:
+		err = process.err
+	case <-emulatorExited:
+		process.stop()
+		err = errEmulatorExited
+	}
+	// the controller is stopped when ackdev is interrupted
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// controllerProcess is a controller running locally.
+type controllerProcess struct {
+	cmd *asyncexec.Cmd
+	// exited is closed when the controller exits, err is then set to the
+	// error returned by the command.
+	exited chan struct{}
+	err    error
+}
+
+// controllerLauncher builds and starts a controller, optionally under a
+// debugger.
+type controllerLauncher struct {
+	runner *controller.Runner
+	repo   *repository.Repository
+	flags  map[string]string
+	// debugPort is the port of the debugger, or 0 if the controller runs
+	// without debugger.
+	debugPort int
+}
+
+// build builds the controller binary, without optimizations if it is
+// debugged.
+func (l *controllerLauncher) build(ctx context.Context, binPath string) error {
+	var buildArgs []string
+	if l.debugPort > 0 {
+		buildArgs = controller.DebugBuildArgs
+	}
+	return l.runner.Build(ctx, l.repo, binPath, buildArgs...)
+}
+
+// start starts a controller binary.
+func (l *controllerLauncher) start(ctx context.Context, binPath string) (*controllerProcess, error) {
+	var cmd *asyncexec.Cmd
+	var err error
+	if l.debugPort > 0 {
+		cmd, err = l.runner.StartDebug(ctx, binPath, l.flags, l.debugPort)
+		if err == nil {
+			fmt.Printf("debugger listening on port %d, attach with `%s`\n", l.debugPort, controller.AttachCommand(l.debugPort))
+		}
+	} else {
+		cmd, err = l.runner.Start(ctx, binPath, l.flags)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &controllerProcess{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		_, p.err = cmd.Wait()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+// stop gracefully stops the controller and waits for it to exit.
+func (p *controllerProcess) stop() {
+	p.cmd.Stop()
+	<-p.exited
+}
@@ -0,0 +1,113 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/watch"
+)
+
+// watchedDirectories are the controller directories watched by
+// `ackdev run controller --watch`.
+var watchedDirectories = []string{"pkg", "apis", "cmd"}
+
+// watchController builds and runs a controller, then rebuilds and restarts it
+// each time its Go files change, until the context is done. When a build
+// fails the last successfully built controller keeps running.
+func watchController(ctx context.Context, launcher *controllerLauncher, binPath string, emulatorExited <-chan struct{}) error {
+	repo := launcher.repo
+	dirs := make([]string, 0, len(watchedDirectories))
+	for _, dir := range watchedDirectories {
+		dirs = append(dirs, filepath.Join(repo.FullPath, dir))
+	}
+	changes := watch.New(dirs, watch.WithExtensions(".go")).Watch(ctx)
+
+	var process *controllerProcess
+	defer func() {
+		if process != nil {
+			process.stop()
+		}
+	}()
+
+	// rebuild builds the controller next to the running one, and only
+	// replaces it if the build succeeds.
+	rebuild := func() error {
+		newBinPath := binPath + ".new"
+		err := launcher.build(ctx, newBinPath)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if process != nil {
+				fmt.Println("build failed, the last successful build keeps running")
+			} else {
+				fmt.Println("build failed, waiting for changes")
+			}
+			return nil
+		}
+
+		if process != nil {
+			fmt.Printf("restarting %s\n", repo.Name)
+			process.stop()
+			process = nil
+		}
+		err = os.Rename(newBinPath, binPath)
+		if err != nil {
+			return err
+		}
+		process, err = launcher.start(ctx, binPath)
+		return err
+	}
+
+	fmt.Printf("building %s\n", repo.Name)
+	err := rebuild()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("watching %s for changes\n", filepath.Join(repo.FullPath, "{pkg,apis,cmd}"))
+
+	for {
+		var processExited <-chan struct{}
+		if process != nil {
+			processExited = process.exited
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case paths, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("%d file(s) changed, rebuilding %s\n", len(paths), repo.Name)
+			err = rebuild()
+			if err != nil {
+				return err
+			}
+		case <-processExited:
+			if process.err != nil {
+				fmt.Printf("the controller exited: %v, waiting for changes\n", process.err)
+			} else {
+				fmt.Println("the controller exited, waiting for changes")
+			}
+			process = nil
+		case <-emulatorExited:
+			return errEmulatorExited
+		}
+	}
+}
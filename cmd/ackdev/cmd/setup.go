@@ -22,16 +22,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
 )
 
 var (
 	optSetupRootDirectory   string
 	optSetupInitialServices string
+	optSetupNonInteractive  bool
 )
 
 func init() {
 	setupCmd.PersistentFlags().StringVar(&optSetupRootDirectory, "root-directory", defaultRootDirectory, "root directory for ACK repositories")
 	setupCmd.PersistentFlags().StringVarP(&optSetupInitialServices, "services", "s", "", "services injected in the generated configuration file")
+	setupCmd.PersistentFlags().BoolVar(&optSetupNonInteractive, "non-interactive", false, "do not prompt for credentials and services, even when attached to a terminal")
 }
 
 var setupCmd = &cobra.Command{
@@ -48,7 +52,7 @@ func setupACKDev(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("ackdev is already setup")
 	}
 
-	initialServices := strings.Split(optSetupInitialServices, ",")
+	initialServices := parseServices(optSetupInitialServices)
 	rootDir, err := filepath.Abs(optSetupRootDirectory)
 	if err != nil {
 		return err
@@ -63,14 +67,51 @@ func setupACKDev(cmd *cobra.Command, args []string) error {
 	newConfig := config.Config{
 		RootDirectory: rootDir,
 		Repositories: config.RepositoriesConfig{
-			Services: initialServices,
+			Services: config.NewRepositoryConfigs(initialServices...),
 			Core:     config.DefaultConfig.Repositories.Core,
 		},
+		Cluster: config.DefaultConfig.Cluster,
+	}
+
+	runEnsure := false
+	if !optSetupNonInteractive && isInteractive() {
+		runEnsure, err = runSetupWizard(cmd.Context(), &newConfig)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = config.Save(&newConfig, ackConfigPath)
 	if err != nil {
 		return err
 	}
-	return nil
+	fmt.Printf("configuration saved to %s\n", ackConfigPath)
+
+	if !runEnsure {
+		return nil
+	}
+
+	repoManager, err := repository.NewManager(&newConfig)
+	if err != nil {
+		return err
+	}
+	err = repoManager.LoadAll()
+	if err != nil {
+		return err
+	}
+	return repoManager.EnsureAll(cmd.Context())
+}
+
+// parseServices parses a comma separated list of services, ignoring empty
+// and duplicated elements.
+func parseServices(s string) []string {
+	services := []string{}
+	for _, service := range strings.Split(s, ",") {
+		service = strings.ToLower(strings.TrimSpace(service))
+		if service == "" || util.InStrings(service, services) {
+			continue
+		}
+		services = append(services, service)
+	}
+	return services
 }
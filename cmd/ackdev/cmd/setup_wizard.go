@@ -0,0 +1,311 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	gogit "gopkg.in/src-d/go-git.v4"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	ackdevgit "github.com/aws-controllers-k8s/dev-tools/pkg/git"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/github"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
+)
+
+const (
+	controllerSuffix = "-controller"
+)
+
+var (
+	defaultSSHKeyNames = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+)
+
+// runSetupWizard interactively completes the given configuration. It asks for
+// Github and git credentials, verifies them, and lets the user pick the service
+// controllers to manage. It returns true if the user wants to ensure (fork and
+// clone) the configured repositories right away.
+func runSetupWizard(ctx context.Context, cfg *config.Config) (bool, error) {
+	fmt.Println("Welcome to ackdev! Let's configure your environment.")
+	fmt.Println()
+
+	if err := setupGithubCredentials(ctx, cfg); err != nil {
+		return false, err
+	}
+	if err := setupGitTransport(cfg); err != nil {
+		return false, err
+	}
+
+	forkPrefix, err := promptString("Prefix of your ACK forks", config.DefaultConfig.Github.ForkPrefix)
+	if err != nil {
+		return false, err
+	}
+	cfg.Github.ForkPrefix = forkPrefix
+
+	if err := setupServices(ctx, cfg); err != nil {
+		return false, err
+	}
+
+	if cfg.Github.Token == "" {
+		fmt.Println("A Github token is needed to fork repositories, you can run " +
+			"`ackdev ensure repos` once you add one to the configuration.")
+		return false, nil
+	}
+	return promptConfirm("Fork and clone the configured repositories now?", false)
+}
+
+// setupGithubCredentials prompts for the Github username and token and
+// verifies them against the Github API.
+func setupGithubCredentials(ctx context.Context, cfg *config.Config) error {
+	for {
+		username, err := promptString("Github username", cfg.Github.Username)
+		if err != nil {
+			return err
+		}
+		token, err := promptSecret("Github token (leave empty to skip)")
+		if err != nil {
+			return err
+		}
+		cfg.Github.Username = username
+		cfg.Github.Token = token
+
+		if token == "" {
+			return nil
+		}
+
+		login, err := verifyGithubToken(ctx, username, token)
+		if err == nil {
+			cfg.Github.Username = login
+			fmt.Printf("Github token verified for user %s\n", login)
+			return nil
+		}
+
+		fmt.Printf("Github token verification failed: %v\n", err)
+		retry, err := promptConfirm("Try again?", true)
+		if err != nil {
+			return err
+		}
+		if !retry {
+			return nil
+		}
+	}
+}
+
+// verifyGithubToken checks that the token is valid and that it belongs to
+// the given user. It returns the token owner login.
+func verifyGithubToken(ctx context.Context, username, token string) (string, error) {
+	user, err := github.NewClient(token).GetAuthenticatedUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	login := user.GetLogin()
+	if username != "" && !strings.EqualFold(login, username) {
+		return "", fmt.Errorf("token belongs to %s, not %s", login, username)
+	}
+	return login, nil
+}
+
+// setupGitTransport prompts for the protocol used to clone repositories and
+// verifies that git can authenticate with it.
+func setupGitTransport(cfg *config.Config) error {
+	defaultProtocol := "https"
+	defaultKeyPath := detectSSHKeyPath()
+	if defaultKeyPath != "" {
+		defaultProtocol = "ssh"
+	}
+
+	for {
+		protocol, err := promptString("Git protocol (ssh|https)", defaultProtocol)
+		if err != nil {
+			return err
+		}
+
+		var gitClient *ackdevgit.Git
+		switch strings.ToLower(protocol) {
+		case "ssh":
+			keyPath, err := promptString("SSH private key path", defaultKeyPath)
+			if err != nil {
+				return err
+			}
+			cfg.Git.SSHKeyPath = keyPath
+			gitClient = ackdevgit.New(ackdevgit.WithSSHKeyPath(keyPath))
+		case "https":
+			cfg.Git.SSHKeyPath = ""
+			gitClient = ackdevgit.New(
+				ackdevgit.WithGithubCredentials(cfg.Github.Username, cfg.Github.Token),
+			)
+		default:
+			fmt.Printf("unsupported protocol: %s\n", protocol)
+			continue
+		}
+
+		url := repository.RemoteURL(cfg, github.ACKOrg, "runtime")
+		err = gitClient.CheckAccess(url)
+		if err == nil {
+			fmt.Printf("git access verified using %s\n", url)
+			return nil
+		}
+
+		fmt.Printf("git access verification failed: %v\n", err)
+		retry, err := promptConfirm("Try again?", true)
+		if err != nil {
+			return err
+		}
+		if !retry {
+			return nil
+		}
+	}
+}
+
+// detectSSHKeyPath returns the path of the first default SSH private key
+// found in $HOME/.ssh
+func detectSSHKeyPath() string {
+	for _, name := range defaultSSHKeyNames {
+		path := filepath.Join(homeDirectory, ".ssh", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// setupServices lets the user pick the service controllers managed by ackdev
+// among the controllers available in the ACK organisation.
+func setupServices(ctx context.Context, cfg *config.Config) error {
+	for _, name := range detectLocalCheckouts(cfg.RootDirectory, config.RepositoryNames(cfg.Repositories.Core)) {
+		fmt.Printf("found existing checkout of %s\n", name)
+	}
+
+	available, err := listAvailableServices(ctx, cfg.Github.Token)
+	if err != nil {
+		fmt.Printf("cannot list ACK service controllers: %v\n", err)
+	}
+
+	controllerNames := make([]string, 0, len(available))
+	for _, service := range available {
+		controllerNames = append(controllerNames, service+controllerSuffix)
+	}
+	selected := config.RepositoryNames(cfg.Repositories.Services)
+	for _, name := range detectLocalCheckouts(cfg.RootDirectory, controllerNames) {
+		service := strings.TrimSuffix(name, controllerSuffix)
+		fmt.Printf("found existing checkout of %s\n", name)
+		if !util.InStrings(service, selected) {
+			selected = append(selected, service)
+		}
+	}
+
+	if len(available) > 0 {
+		fmt.Println()
+		fmt.Println("Available service controllers:")
+		printServiceChoices(available, selected)
+		fmt.Println()
+	}
+
+	for {
+		answer, err := promptString(
+			"Services to manage (comma separated names or numbers)",
+			strings.Join(selected, ","),
+		)
+		if err != nil {
+			return err
+		}
+		services, err := resolveServiceChoices(answer, available)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		cfg.Repositories.Services = config.NewRepositoryConfigs(services...)
+		return nil
+	}
+}
+
+// listAvailableServices returns the sorted list of the services that have a
+// controller repository in the ACK organisation.
+func listAvailableServices(ctx context.Context, token string) ([]string, error) {
+	repos, err := github.NewClient(token).ListOrganizationRepositories(ctx, github.ACKOrg)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []string{}
+	for _, repo := range repos {
+		name := repo.GetName()
+		if strings.HasSuffix(name, controllerSuffix) && !repo.GetArchived() {
+			services = append(services, strings.TrimSuffix(name, controllerSuffix))
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// detectLocalCheckouts returns the names of the repositories that are already
+// cloned in the root directory.
+func detectLocalCheckouts(rootDir string, names []string) []string {
+	found := []string{}
+	for _, name := range names {
+		_, err := gogit.PlainOpen(filepath.Join(rootDir, name))
+		if err == nil {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// printServiceChoices prints a numbered list of services, marking the selected
+// ones.
+func printServiceChoices(services []string, selected []string) {
+	tw := newTable()
+	defer tw.Render()
+
+	const columns = 4
+	row := []string{}
+	for i, service := range services {
+		marker := " "
+		if util.InStrings(service, selected) {
+			marker = "*"
+		}
+		row = append(row, fmt.Sprintf("%3d) %s %s", i+1, marker, service))
+		if len(row) == columns || i == len(services)-1 {
+			tw.Append(row)
+			row = []string{}
+		}
+	}
+}
+
+// resolveServiceChoices parses the services picked by the user. Each choice can
+// either be a service name or its index in the list of available services.
+func resolveServiceChoices(answer string, available []string) ([]string, error) {
+	services := []string{}
+	for _, choice := range parseServices(answer) {
+		if index, err := strconv.Atoi(choice); err == nil {
+			if index < 1 || index > len(available) {
+				return nil, fmt.Errorf("invalid choice: %d", index)
+			}
+			choice = available[index-1]
+		} else if len(available) > 0 && !util.InStrings(choice, available) {
+			return nil, fmt.Errorf("unknown service controller: %s", choice)
+		}
+		if !util.InStrings(choice, services) {
+			services = append(services, choice)
+		}
+	}
+	return services, nil
+}
@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// signalContext returns a context cancelled when ackdev receives an interrupt
+// or termination signal. Commands started with this context are asked to
+// terminate, which forwards the signal to child processes running in their
+// own process groups. A second signal exits immediately.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signalCh := make(chan os.Signal, 2)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signalCh:
+			fmt.Fprintln(os.Stderr, "interrupted, stopping running commands (press Ctrl-C again to force exit)")
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signalCh)
+			return
+		}
+		<-signalCh
+		os.Exit(130)
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/controller"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/deploy"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/e2e"
+)
+
+const (
+	controllerLogFileName = "controller.log"
+	pytestLogFileName     = "pytest.log"
+)
+
+var (
+	optTestE2EMarkers            string
+	optTestE2EShowControllerLogs bool
+)
+
+func init() {
+	testE2ECmd.PersistentFlags().StringVarP(&optTestE2EMarkers, "markers", "m", "", "pytest marker expression selecting the tests to run")
+	testE2ECmd.PersistentFlags().BoolVar(&optTestE2EShowControllerLogs, "show-controller-logs", false, "stream the controller logs, in addition to saving them in the artifacts directory")
+	testE2ECmd.PersistentFlags().StringVar(&optClusterName, "cluster", "", "kind cluster name, overrides the configured name")
+}
+
+var testE2ECmd = &cobra.Command{
+	Use:     "e2e <service> [-- pytest args...]",
+	RunE:    testE2E,
+	PreRunE: requireDependencies("go", "kind", "kubectl", "python3"),
+	Args:    cobra.MinimumNArgs(1),
+	Short:   "Run a service controller e2e tests against the controller running locally",
+	Example: "ackdev test e2e s3 -m \"not slow\" -- -k bucket",
+}
+
+func testE2E(cmd *cobra.Command, args []string) error {
+	cfg, repo, err := loadControllerRepository(args[0])
+	if err != nil {
+		return err
+	}
+	testInfraPath, err := coreRepositoryPath(cfg, testInfraRepositoryName)
+	if err != nil {
+		return err
+	}
+	profile, err := runProfile(cfg, "", repo)
+	if err != nil {
+		return err
+	}
+
+	artifactsDir, err := e2e.ArtifactsDirectory(stateDirectory(cfg), repo, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("artifacts directory: %s\n", artifactsDir)
+
+	out := newCommandOutput()
+	defer out.Close()
+	ctx := cmd.Context()
+
+	// Create the cluster if needed and install the controller CRDs
+	clusterManager := newClusterManager(cfg, out)
+	err = clusterManager.Up(ctx)
+	if err != nil {
+		return err
+	}
+	err = deploy.NewDeployer(clusterManager, deploy.WithOutput(out)).ApplyCRDs(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	// Run the controller locally, saving its logs in the artifacts directory
+	controllerStdout, controllerStderr := ioutil.Discard, ioutil.Discard
+	if optTestE2EShowControllerLogs {
+		controllerStdout, controllerStderr = os.Stdout, os.Stderr
+	}
+	mux := asyncexec.NewMultiplexer(controllerStdout, controllerStderr, asyncexec.WithColor(isInteractive()))
+	controllerOut, err := mux.NewSource("controller", asyncexec.WithLogFile(filepath.Join(artifactsDir, controllerLogFileName)))
+	if err != nil {
+		return err
+	}
+	defer controllerOut.Close()
+
+	runner := controller.NewRunner(
+		controller.WithOutput(controllerOut),
+		controller.WithEnv(profile.EnvList()...),
+		controller.WithEnv("KUBECONFIG="+clusterManager.KubeconfigPath()),
+	)
+	binPath := filepath.Join(artifactsDir, repo.Name)
+	fmt.Printf("building %s\n", repo.Name)
+	err = runner.Build(ctx, repo, binPath)
+	if err != nil {
+		return err
+	}
+
+	controllerCtx, stopController := context.WithCancel(ctx)
+	defer stopController()
+	controllerCmd, err := runner.Start(controllerCtx, binPath, profile.Flags)
+	if err != nil {
+		return err
+	}
+	controllerExited := make(chan struct{})
+	go func() {
+		controllerCmd.Wait()
+		close(controllerExited)
+	}()
+
+	// Run the tests, saving their output in the artifacts directory
+	pytestOut, err := asyncexec.NewMultiplexer(os.Stdout, os.Stderr).NewSource("", asyncexec.WithLogFile(filepath.Join(artifactsDir, pytestLogFileName)))
+	if err != nil {
+		return err
+	}
+	defer pytestOut.Close()
+
+	testErr := e2e.NewRunner(testInfraPath, e2e.WithOutput(pytestOut)).Run(ctx, repo, e2e.Options{
+		Markers:            optTestE2EMarkers,
+		Args:               args[1:],
+		Kubeconfig:         clusterManager.KubeconfigPath(),
+		Flags:              profile.Flags,
+		ArtifactsDirectory: artifactsDir,
+	})
+
+	select {
+	case <-controllerExited:
+		fmt.Printf("the controller exited before the end of the tests, see %s\n", filepath.Join(artifactsDir, controllerLogFileName))
+	default:
+		stopController()
+		<-controllerExited
+	}
+
+	fmt.Printf("logs and results saved in %s\n", artifactsDir)
+	return testErr
+}
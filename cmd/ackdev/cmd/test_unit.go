@@ -0,0 +1,238 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/gotest"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+)
+
+var (
+	testUnitTableHeaderColumns   = []string{"Name", "Passed", "Failed", "Skipped", "Duration", "Status"}
+	testUnitSlowestTableColumns  = []string{"Repository", "Test", "Package", "Duration"}
+	testUnitFailuresTableColumns = []string{"Repository", "Test", "Package"}
+
+	optTestUnitFilterExpression string
+	optTestUnitConcurrency      int
+	optTestUnitJUnitDirectory   string
+	optTestUnitSlowest          int
+)
+
+func init() {
+	testUnitCmd.PersistentFlags().StringVarP(&optTestUnitFilterExpression, "filter", "f", "", "filter expression")
+	testUnitCmd.PersistentFlags().IntVarP(&optTestUnitConcurrency, "concurrency", "j", 4, "maximum number of repositories tested at the same time")
+	testUnitCmd.PersistentFlags().StringVar(&optTestUnitJUnitDirectory, "junit-dir", "", "directory where the JUnit reports are written, in <repository>.xml files")
+	testUnitCmd.PersistentFlags().IntVar(&optTestUnitSlowest, "slowest", 5, "number of slowest tests displayed for each repository")
+}
+
+var testUnitCmd = &cobra.Command{
+	Use:     "unit [-- go test args...]",
+	RunE:    testUnit,
+	PreRunE: requireDependencies("go"),
+	Short:   "Run the unit tests of multiple repositories",
+	Example: "ackdev test unit -f type=controller -- -race",
+}
+
+// testUnitResult is the outcome of the unit tests of a repository.
+type testUnitResult struct {
+	repo     *repository.Repository
+	report   *gotest.Report
+	duration time.Duration
+	err      error
+}
+
+func testUnit(cmd *cobra.Command, args []string) error {
+	if optTestUnitConcurrency < 1 {
+		return fmt.Errorf("concurrency must be greater than 0")
+	}
+
+	cfg, err := config.Load(ackConfigPath)
+	if err != nil {
+		return err
+	}
+	filters, err := repository.BuildFilters(optTestUnitFilterExpression)
+	if err != nil {
+		return err
+	}
+	repos, err := listRepositories(filters...)
+	if err != nil {
+		return err
+	}
+
+	junitDir := optTestUnitJUnitDirectory
+	if junitDir == "" {
+		junitDir, err = gotest.ArtifactsDirectory(stateDirectory(cfg), time.Now())
+	} else {
+		err = os.MkdirAll(junitDir, os.ModePerm)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only run the tests of repositories that exist locally
+	cloned := []*repository.Repository{}
+	for _, repo := range repos {
+		if !repo.Cloned() {
+			fmt.Printf("skipping %s: repository is not cloned, please run `ackdev ensure repos`\n", repo.Name)
+			continue
+		}
+		cloned = append(cloned, repo)
+	}
+
+	runner := gotest.NewRunner()
+	results := make([]*testUnitResult, len(cloned))
+	sem := make(chan struct{}, optTestUnitConcurrency)
+	var wg sync.WaitGroup
+	for i, repo := range cloned {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo *repository.Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fmt.Printf("testing %s\n", repo.Name)
+			start := time.Now()
+			report, err := runner.Run(cmd.Context(), repo, args...)
+			results[i] = &testUnitResult{
+				repo:     repo,
+				report:   report,
+				duration: time.Since(start),
+				err:      err,
+			}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		err = writeJUnitReport(filepath.Join(junitDir, result.repo.Name+".xml"), result)
+		if err != nil {
+			return err
+		}
+	}
+
+	tablePrintTestUnitFailures(results)
+	if optTestUnitSlowest > 0 {
+		tablePrintTestUnitSlowest(results)
+	}
+	tablePrintTestUnitResults(results)
+	fmt.Printf("JUnit reports saved in %s\n", junitDir)
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil || result.report.Failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("tests failed in %d/%d repositories", failed, len(results))
+	}
+	return nil
+}
+
+func writeJUnitReport(path string, result *testUnitResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return result.report.WriteJUnit(f, result.repo.Name)
+}
+
+func tablePrintTestUnitFailures(results []*testUnitResult) {
+	rows := [][]string{}
+	for _, result := range results {
+		for _, pkg := range result.report.Packages() {
+			if pkg.Status == gotest.StatusFail && len(pkg.Tests) == 0 {
+				rows = append(rows, []string{result.repo.Name, "-", pkg.Name})
+			}
+			for _, test := range pkg.Tests {
+				if test.Status == gotest.StatusFail {
+					rows = append(rows, []string{result.repo.Name, test.Name, test.Package})
+				}
+			}
+		}
+		// go test failed before running any package, e.g. because of an
+		// invalid go.mod file.
+		if result.err != nil && len(result.report.Packages()) == 0 {
+			fmt.Printf("%s: %s\n", result.repo.Name, result.err)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println("Failed tests:")
+	tw := newTable()
+	tw.SetHeader(testUnitFailuresTableColumns)
+	tw.AppendBulk(rows)
+	tw.Render()
+	fmt.Println()
+}
+
+func tablePrintTestUnitSlowest(results []*testUnitResult) {
+	rows := [][]string{}
+	for _, result := range results {
+		for _, test := range result.report.Slowest(optTestUnitSlowest) {
+			rows = append(rows, []string{
+				result.repo.Name,
+				test.Name,
+				test.Package,
+				test.Elapsed.Round(time.Millisecond).String(),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println("Slowest tests:")
+	tw := newTable()
+	tw.SetHeader(testUnitSlowestTableColumns)
+	tw.AppendBulk(rows)
+	tw.Render()
+	fmt.Println()
+}
+
+func tablePrintTestUnitResults(results []*testUnitResult) {
+	tw := newTable()
+	defer tw.Render()
+
+	tw.SetHeader(testUnitTableHeaderColumns)
+
+	for _, result := range results {
+		passed, failed, skipped := result.report.Counts()
+		status := "PASS"
+		if result.err != nil || result.report.Failed() {
+			status = "FAIL"
+		}
+		tw.Append([]string{
+			result.repo.Name,
+			strconv.Itoa(passed),
+			strconv.Itoa(failed),
+			strconv.Itoa(skipped),
+			result.duration.Round(time.Millisecond).String(),
+			status,
+		})
+	}
+}
@@ -2,102 +2,291 @@ package asyncexec
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"os/exec"
+	"sync"
+	"time"
 )
 
+const (
+	// DefaultGracePeriod is the time given to a command to exit after being
+	// asked to terminate, before it gets killed.
+	DefaultGracePeriod = 10 * time.Second
+	// DefaultTailLines is the number of output lines kept in a command Result.
+	DefaultTailLines = 20
+)
+
+var (
+	ErrAlreadyStarted error = errors.New("command already started")
+	ErrNotStarted     error = errors.New("command not started")
+)
+
+// Output receives the lines written by a command, without their trailing
+// newline. Lines are written sequentially for each stream, but stdout and
+// stderr lines can be written concurrently.
+type Output interface {
+	WriteStdout(line []byte) error
+	WriteStderr(line []byte) error
+}
+
+// Result describes a command that exited.
+type Result struct {
+	// ExitCode is the process exit code, or -1 if it was killed by a signal.
+	ExitCode int
+	// Duration is the time elapsed between the start and the exit of the
+	// command.
+	Duration time.Duration
+	// Stdout contains the last lines written to stdout.
+	Stdout []string
+	// Stderr contains the last lines written to stderr.
+	Stderr []string
+}
+
+// Option is a function modifying a Cmd.
+type Option func(*Cmd)
+
+// WithContext sets the context of a command. The command is stopped, as if
+// Stop was called, when the context is done.
+func WithContext(ctx context.Context) Option {
+	return func(c *Cmd) {
+		c.ctx = ctx
+	}
+}
+
+// WithGracePeriod sets the time given to the command to exit after receiving
+// SIGTERM, before it gets killed.
+func WithGracePeriod(d time.Duration) Option {
+	return func(c *Cmd) {
+		c.gracePeriod = d
+	}
+}
+
+// WithOutput streams the command output lines to out.
+func WithOutput(out Output) Option {
+	return func(c *Cmd) {
+		c.output = out
+	}
+}
+
+// WithTailLines sets the number of output lines kept in the command Result.
+func WithTailLines(n int) Option {
+	return func(c *Cmd) {
+		c.tailLines = n
+	}
+}
+
 // New instantiate a new Cmd object.
-func New(cmd *exec.Cmd, buff int) *Cmd {
-	return &Cmd{
-		cmd:      cmd,
-		stopCh:   make(chan struct{}),
-		stdoutCh: make(chan []byte, buff),
-		stderrCh: make(chan []byte, buff),
+func New(cmd *exec.Cmd, opts ...Option) *Cmd {
+	c := &Cmd{
+		cmd:         cmd,
+		ctx:         context.Background(),
+		gracePeriod: DefaultGracePeriod,
+		tailLines:   DefaultTailLines,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Cmd is a wrapper arround exec.Cmd. Mainly used to execute
 // command asynchronously with/or without output stream.
+//
+// The command runs in its own process group, so that stopping it also stops
+// the processes it spawned.
 type Cmd struct {
 	cmd *exec.Cmd
 
+	ctx         context.Context
+	gracePeriod time.Duration
+	output      Output
+	tailLines   int
+
+	started   bool
+	startTime time.Time
+	readers   sync.WaitGroup
+	stdout    *tail
+	stderr    *tail
+
+	stopOnce sync.Once
 	stopCh   chan struct{}
-	stdoutCh chan []byte
-	stderrCh chan []byte
+	doneOnce sync.Once
+	doneCh   chan struct{}
 }
 
-// Run runs the command. if streamOutput is true, it will spin
-// two goroutine responsible of streaming the stdout and stderr
-func (c *Cmd) Run() error {
+// Run starts the command and waits for it to exit. See Wait.
+func (c *Cmd) Run() (*Result, error) {
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	return c.Wait()
+}
+
+// Start starts the command without waiting for it to exit. Its output is
+// read by two goroutines, streaming it to the command Output if any.
+func (c *Cmd) Start() error {
+	if c.started {
+		return ErrAlreadyStarted
+	}
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
 	cmdStdoutReader, err := c.cmd.StdoutPipe()
 	if err != nil {
 		return err
 	}
-	stdoutScanner := bufio.NewScanner(cmdStdoutReader)
-
 	cmdStderrReader, err := c.cmd.StderrPipe()
 	if err != nil {
 		return err
 	}
-	stderrScanner := bufio.NewScanner(cmdStderrReader)
-
-	// Goroutine for stdout
-	go func() {
-		defer close(c.stdoutCh)
-		for stdoutScanner.Scan() {
-			bytes := stdoutScanner.Bytes()
-			c.stdoutCh <- bytes
-		}
-	}()
-
-	// Goroutine for stderr
-	go func() {
-		defer close(c.stderrCh)
-		for stderrScanner.Scan() {
-			bytes := stderrScanner.Bytes()
-			c.stderrCh <- bytes
-		}
-	}()
 
+	setProcessGroup(c.cmd)
 	err = c.cmd.Start()
 	if err != nil {
 		return err
 	}
+	c.started = true
+	c.startTime = time.Now()
+
+	c.stdout = newTail(c.tailLines)
+	c.stderr = newTail(c.tailLines)
+	var writeStdout, writeStderr func([]byte) error
+	if c.output != nil {
+		writeStdout, writeStderr = c.output.WriteStdout, c.output.WriteStderr
+	}
+
+	// Goroutines for stdout and stderr
+	c.readers.Add(2)
+	go c.readLines(cmdStdoutReader, c.stdout, writeStdout)
+	go c.readLines(cmdStderrReader, c.stderr, writeStderr)
 
-	// listening for stop signal
-	go func() {
-		<-c.stopCh
-		c.cmd.Process.Kill()
-	}()
+	// listening for stop signal and context cancellation
+	go c.watch()
 
 	return nil
 }
 
-// Exited returns true if the command exited, false otherwise.
-func (c *Cmd) Exited() bool {
-	return c.cmd.ProcessState.Exited()
+// watch terminates the command when it is stopped or when its context is
+// done, and kills it if it is still running after the grace period.
+func (c *Cmd) watch() {
+	select {
+	case <-c.doneCh:
+		return
+	case <-c.stopCh:
+	case <-c.ctx.Done():
+	}
+
+	terminateProcessGroup(c.cmd.Process)
+	select {
+	case <-c.doneCh:
+	case <-time.After(c.gracePeriod):
+		killProcessGroup(c.cmd.Process)
+	}
 }
 
-// ExitCode returns the command process exit code.
-func (c *Cmd) ExitCode() int {
-	return c.cmd.ProcessState.ExitCode()
+// Wait blocks until the command output is fully read and the command exits.
+// The returned Result is only nil if the command was not started. The error
+// is an *exec.ExitError if the command exited with a non zero code, the
+// context error if it was stopped because its context is done, or the first
+// error returned by the command Output.
+func (c *Cmd) Wait() (*Result, error) {
+	if !c.started {
+		return nil, ErrNotStarted
+	}
+
+	// os/exec requires the pipes to be fully read before calling Wait.
+	c.readers.Wait()
+	err := c.cmd.Wait()
+	duration := time.Since(c.startTime)
+	c.doneOnce.Do(func() { close(c.doneCh) })
+
+	result := &Result{
+		ExitCode: c.cmd.ProcessState.ExitCode(),
+		Duration: duration,
+		Stdout:   c.stdout.lines(),
+		Stderr:   c.stderr.lines(),
+	}
+	if ctxErr := c.ctx.Err(); ctxErr != nil && err != nil {
+		return result, ctxErr
+	}
+	if err != nil {
+		return result, err
+	}
+	if err := c.stdout.writeErr; err != nil {
+		return result, err
+	}
+	return result, c.stderr.writeErr
 }
 
-// StdoutStream returns a channel streaming the command Stdout.
-func (c *Cmd) StdoutStream() <-chan []byte {
-	return c.stdoutCh
+// Stop signals the Wrapper to terminate the process running the command. The
+// process group receives SIGTERM, and is killed if it is still running after
+// the grace period. Stop doesn't block and can be called multiple times.
+func (c *Cmd) Stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
 }
 
-// StderrStream returns a channel streaming the command Stderr.
-func (c *Cmd) StderrStream() <-chan []byte {
-	return c.stderrCh
+// readLines reads r line by line, keeping the last lines in t and passing each
+// line, without its trailing newline, to write. Unlike bufio.Scanner it doesn't
+// limit the length of a line, and every line is passed in its own buffer.
+func (c *Cmd) readLines(r io.Reader, t *tail, write func([]byte) error) {
+	defer c.readers.Done()
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line = trimCR(bytes.TrimSuffix(line, []byte{'\n'}))
+			t.add(line)
+			// keep reading after a write error so the command is never
+			// blocked on a full pipe.
+			if write != nil && t.writeErr == nil {
+				t.writeErr = write(line)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
 }
 
-// Wait blocks until the command exits
-func (c *Cmd) Wait() error {
-	return c.cmd.Wait()
+// tail keeps the last lines of an output stream.
+type tail struct {
+	ring  []string
+	next  int
+	count int
+
+	writeErr error
 }
 
-// Stop signals the Wrapper to kill the process running the command.
-func (c *Cmd) Stop() {
-	c.stopCh <- struct{}{}
+func newTail(size int) *tail {
+	return &tail{ring: make([]string, size)}
+}
+
+func (t *tail) add(line []byte) {
+	if len(t.ring) == 0 {
+		return
+	}
+	t.ring[t.next] = string(line)
+	t.next = (t.next + 1) % len(t.ring)
+	if t.count < len(t.ring) {
+		t.count++
+	}
+}
+
+// lines returns the kept lines, oldest first.
+func (t *tail) lines() []string {
+	lines := make([]string, 0, t.count)
+	if t.count == 0 {
+		return lines
+	}
+	start := (t.next - t.count + len(t.ring)) % len(t.ring)
+	for i := 0; i < t.count; i++ {
+		lines = append(lines, t.ring[(start+i)%len(t.ring)])
+	}
+	return lines
 }
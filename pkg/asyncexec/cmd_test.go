@@ -3,42 +3,295 @@
 package asyncexec_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
 )
 
 func ExampleCmd_Run_withNoStream() {
-	cmd := asyncexec.New(exec.Command("echo", "Hello ACK"), 16)
-	cmd.Run()
-	cmd.Wait()
-	fmt.Println(cmd.ExitCode())
-	// Output: 0
+	result, _ := asyncexec.New(exec.Command("echo", "Hello ACK")).Run()
+	fmt.Println(result.ExitCode, result.Stdout)
+	// Output: 0 [Hello ACK]
 }
 
 func ExampleCmd_Run_withStream() {
-	cmd := asyncexec.New(exec.Command("echo", "Hello ACK"), 16)
-	cmd.Run()
+	out, err := asyncexec.NewMultiplexer(os.Stdout, os.Stdout).NewSource("")
+	if err != nil {
+		panic(err)
+	}
+	defer out.Close()
+
+	asyncexec.New(exec.Command("echo", "Hello ACK"), asyncexec.WithOutput(out)).Run()
+	// Output: Hello ACK
+}
+
+// lineRecorder records the lines written by a command.
+type lineRecorder struct {
+	mu     sync.Mutex
+	stdout []string
+	stderr []string
+}
+
+func (r *lineRecorder) WriteStdout(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stdout = append(r.stdout, string(line))
+	return nil
+}
+
+func (r *lineRecorder) WriteStderr(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stderr = append(r.stderr, string(line))
+	return nil
+}
+
+// failingOutput fails to write any line.
+type failingOutput struct{}
+
+func (failingOutput) WriteStdout(line []byte) error { return errors.New("stdout is closed") }
+func (failingOutput) WriteStderr(line []byte) error { return nil }
+
+func TestCmd_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       string
+		opts         []asyncexec.Option
+		wantExitCode int
+		wantErr      bool
+		wantStdout   []string
+		wantStderr   []string
+	}{
+		{
+			name:         "success",
+			script:       "echo hello; echo world >&2",
+			wantExitCode: 0,
+			wantStdout:   []string{"hello"},
+			wantStderr:   []string{"world"},
+		},
+		{
+			name:         "non zero exit code",
+			script:       "echo oops >&2; exit 3",
+			wantExitCode: 3,
+			wantErr:      true,
+			wantStdout:   []string{},
+			wantStderr:   []string{"oops"},
+		},
+		{
+			name:         "trailing output without newline",
+			script:       "printf 'first\\nlast'",
+			wantExitCode: 0,
+			wantStdout:   []string{"first", "last"},
+			wantStderr:   []string{},
+		},
+		{
+			name:         "tail",
+			script:       "for i in 1 2 3 4 5; do echo $i; done",
+			opts:         []asyncexec.Option{asyncexec.WithTailLines(2)},
+			wantExitCode: 0,
+			wantStdout:   []string{"4", "5"},
+			wantStderr:   []string{},
+		},
+		{
+			name:         "no tail",
+			script:       "echo hello",
+			opts:         []asyncexec.Option{asyncexec.WithTailLines(0)},
+			wantExitCode: 0,
+			wantStdout:   []string{},
+			wantStderr:   []string{},
+		},
+		{
+			name:         "output error",
+			script:       "echo hello",
+			opts:         []asyncexec.Option{asyncexec.WithOutput(failingOutput{})},
+			wantExitCode: 0,
+			wantErr:      true,
+			wantStdout:   []string{"hello"},
+			wantStderr:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := asyncexec.New(exec.Command("sh", "-c", tt.script), tt.opts...).Run()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantExitCode, result.ExitCode)
+			assert.Equal(t, tt.wantStdout, result.Stdout)
+			assert.Equal(t, tt.wantStderr, result.Stderr)
+			assert.True(t, result.Duration > 0)
+		})
+	}
+}
+
+func TestCmd_Start(t *testing.T) {
+	cmd := asyncexec.New(exec.Command("this-command-does-not-exist"))
+	assert.Error(t, cmd.Start())
+
+	_, err := cmd.Wait()
+	assert.Equal(t, asyncexec.ErrNotStarted, err)
+
+	cmd = asyncexec.New(exec.Command("true"))
+	require.NoError(t, cmd.Start())
+	assert.Equal(t, asyncexec.ErrAlreadyStarted, cmd.Start())
+	_, err = cmd.Wait()
+	assert.NoError(t, err)
+}
+
+func TestCmd_Run_largeOutput(t *testing.T) {
+	// much more than the pipe buffers, with lines longer than the
+	// bufio.Scanner default limit of 64KB
+	const lines, length = 50, 200 * 1024
+	script := fmt.Sprintf("for i in $(seq 1 %d); do head -c %d /dev/zero | tr '\\0' a; echo; done; echo done", lines, length)
+
+	recorder := &lineRecorder{}
+	result, err := asyncexec.New(exec.Command("sh", "-c", script), asyncexec.WithOutput(recorder)).Run()
+	require.NoError(t, err)
+
+	require.Len(t, recorder.stdout, lines+1)
+	for _, line := range recorder.stdout[:lines] {
+		assert.Equal(t, length, len(line))
+	}
+	// trailing output is never lost
+	assert.Equal(t, "done", recorder.stdout[lines])
+	assert.Equal(t, "done", result.Stdout[len(result.Stdout)-1])
+}
+
+func TestCmd_Run_interleavedOutput(t *testing.T) {
+	const lines = 5000
+	script := fmt.Sprintf("for i in $(seq 1 %d); do echo out $i; echo err $i >&2; done", lines)
+
+	recorder := &lineRecorder{}
+	result, err := asyncexec.New(exec.Command("sh", "-c", script), asyncexec.WithOutput(recorder)).Run()
+	require.NoError(t, err)
+
+	// lines of each stream are received in order
+	require.Len(t, recorder.stdout, lines)
+	require.Len(t, recorder.stderr, lines)
+	for i := 0; i < lines; i++ {
+		assert.Equal(t, fmt.Sprintf("out %d", i+1), recorder.stdout[i])
+		assert.Equal(t, fmt.Sprintf("err %d", i+1), recorder.stderr[i])
+	}
+	assert.Len(t, result.Stdout, asyncexec.DefaultTailLines)
+	assert.Equal(t, fmt.Sprintf("err %d", lines), result.Stderr[len(result.Stderr)-1])
+}
+
+func TestCmd_Run_concurrentCommands(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	m := asyncexec.NewMultiplexer(stdout, ioutil.Discard)
+
+	const commands, lines = 8, 1000
+	var wg sync.WaitGroup
+	for i := 0; i < commands; i++ {
+		s, err := m.NewSource(fmt.Sprintf("cmd-%d", i))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(s *asyncexec.Source) {
+			defer wg.Done()
+			defer s.Close()
+			script := fmt.Sprintf("for i in $(seq 1 %d); do echo line $i; done", lines)
+			_, err := asyncexec.New(exec.Command("sh", "-c", script), asyncexec.WithOutput(s)).Run()
+			assert.NoError(t, err)
+		}(s)
+	}
+	wg.Wait()
+
+	output := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	require.Len(t, output, commands*lines)
+	for _, line := range output {
+		assert.Regexp(t, `^cmd-\d \| line \d+$`, line)
+	}
+}
+
+func TestCmd_Stop_afterExit(t *testing.T) {
+	cmd := asyncexec.New(exec.Command("true"))
+	_, err := cmd.Run()
+	require.NoError(t, err)
 
 	done := make(chan struct{})
 	go func() {
-		for b := range cmd.StdoutStream() {
-			fmt.Println(string(b))
-
-		}
-		done <- struct{}{}
+		cmd.Stop()
+		cmd.Stop()
+		close(done)
 	}()
-	go func() {
-		for b := range cmd.StderrStream() {
-			fmt.Println(string(b))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked after the command exited")
+	}
+}
 
-		}
-		done <- struct{}{}
-	}()
+func TestCmd_Stop(t *testing.T) {
+	// the child process must be stopped as well, or it would keep the output
+	// pipes open.
+	cmd := asyncexec.New(exec.Command("sh", "-c", "sleep 30 & sleep 30"))
+	require.NoError(t, cmd.Start())
 
-	defer func() { _, _ = <-done, <-done }()
+	start := time.Now()
+	cmd.Stop()
+	result, err := cmd.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, -1, result.ExitCode)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
 
-	cmd.Wait()
-	// Output: Hello ACK
+func TestCmd_WithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := asyncexec.New(exec.Command("sleep", "30"), asyncexec.WithContext(ctx)).Run()
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// commands are not started once the context is done
+	_, err = asyncexec.New(exec.Command("true"), asyncexec.WithContext(ctx)).Run()
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestCmd_WithGracePeriod(t *testing.T) {
+	// the shell ignores SIGTERM and must be killed once the grace period expires
+	ready := make(chan struct{})
+	recorder := &readyRecorder{ready: ready}
+	cmd := asyncexec.New(
+		exec.Command("sh", "-c", "trap '' TERM; echo ready; while true; do sleep 0.1; done"),
+		asyncexec.WithGracePeriod(200*time.Millisecond),
+		asyncexec.WithOutput(recorder),
+	)
+	require.NoError(t, cmd.Start())
+	<-ready
+
+	start := time.Now()
+	cmd.Stop()
+	_, err := cmd.Wait()
+	assert.Error(t, err)
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(200*time.Millisecond))
+	assert.Less(t, int64(elapsed), int64(5*time.Second))
+}
+
+// readyRecorder closes ready when the first stdout line is written.
+type readyRecorder struct {
+	lineRecorder
+	once  sync.Once
+	ready chan struct{}
+}
+
+func (r *readyRecorder) WriteStdout(line []byte) error {
+	r.once.Do(func() { close(r.ready) })
+	return r.lineRecorder.WriteStdout(line)
 }
@@ -0,0 +1,231 @@
+package asyncexec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	// labelColors are the ANSI colour codes assigned, in order, to the sources
+	// of a Multiplexer.
+	labelColors = []int{36, 33, 32, 35, 34, 96, 93, 92, 95, 94}
+)
+
+// Multiplexer writes the output of several sources to shared stdout and
+// stderr writers. Each line is tagged with the label of the source it comes
+// from, and lines written concurrently are never interleaved.
+type Multiplexer struct {
+	mu sync.Mutex
+
+	stdout io.Writer
+	stderr io.Writer
+
+	color      bool
+	labelWidth int
+	sources    int
+}
+
+// MultiplexerOption is a function modifying a Multiplexer.
+type MultiplexerOption func(*Multiplexer)
+
+// WithColor enables or disables the colourisation of the source labels.
+func WithColor(enabled bool) MultiplexerOption {
+	return func(m *Multiplexer) {
+		m.color = enabled
+	}
+}
+
+// WithLabelWidth pads the source labels to the given width, aligning the
+// lines of all the sources.
+func WithLabelWidth(width int) MultiplexerOption {
+	return func(m *Multiplexer) {
+		m.labelWidth = width
+	}
+}
+
+// NewMultiplexer instantiates a new Multiplexer writing to the given writers.
+func NewMultiplexer(stdout, stderr io.Writer, opts ...MultiplexerOption) *Multiplexer {
+	m := &Multiplexer{
+		stdout: stdout,
+		stderr: stderr,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewSource returns a new source whose lines are tagged with the given label.
+// Lines of a source with an empty label are written as they are.
+func (m *Multiplexer) NewSource(label string, opts ...SourceOption) (*Source, error) {
+	m.mu.Lock()
+	color := labelColors[m.sources%len(labelColors)]
+	m.sources++
+	m.mu.Unlock()
+
+	s := &Source{
+		m:      m,
+		prefix: m.prefix(label, color),
+	}
+	s.stdout = &lineWriter{write: s.WriteStdout}
+	s.stderr = &lineWriter{write: s.WriteStderr}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// prefix returns the prefix written before each line of a source.
+func (m *Multiplexer) prefix(label string, color int) []byte {
+	if label == "" {
+		return nil
+	}
+	label = fmt.Sprintf("%-*s", m.labelWidth, label)
+	if m.color {
+		label = fmt.Sprintf("\x1b[%dm%s\x1b[0m", color, label)
+	}
+	return []byte(label + " | ")
+}
+
+// writeLine writes a prefixed line to w, adding the trailing newline.
+func (m *Multiplexer) writeLine(w io.Writer, prefix, line []byte) error {
+	buf := make([]byte, 0, len(prefix)+len(line)+1)
+	buf = append(buf, prefix...)
+	buf = append(buf, line...)
+	buf = append(buf, '\n')
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := w.Write(buf)
+	return err
+}
+
+// Source is a labelled source of lines written through a Multiplexer.
+type Source struct {
+	m      *Multiplexer
+	prefix []byte
+
+	stdout *lineWriter
+	stderr *lineWriter
+
+	logMu   sync.Mutex
+	logFile *os.File
+}
+
+// SourceOption is a function modifying a Source.
+type SourceOption func(*Source) error
+
+// WithLogFile tees the lines of a source, without their label, to the file
+// at the given path. The file is truncated if it already exists.
+func WithLogFile(path string) SourceOption {
+	return func(s *Source) error {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		s.logFile = f
+		return nil
+	}
+}
+
+// WriteStdout writes a line, without its trailing newline, to the
+// multiplexer stdout.
+func (s *Source) WriteStdout(line []byte) error {
+	return s.writeLine(s.m.stdout, line)
+}
+
+// WriteStderr writes a line, without its trailing newline, to the
+// multiplexer stderr.
+func (s *Source) WriteStderr(line []byte) error {
+	return s.writeLine(s.m.stderr, line)
+}
+
+func (s *Source) writeLine(w io.Writer, line []byte) error {
+	if s.logFile != nil {
+		s.logMu.Lock()
+		_, err := s.logFile.Write(append(line[:len(line):len(line)], '\n'))
+		s.logMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return s.m.writeLine(w, s.prefix, line)
+}
+
+// Stdout returns a writer splitting the written bytes into lines written to
+// the multiplexer stdout. Incomplete lines are buffered until Close is called.
+func (s *Source) Stdout() io.Writer {
+	return s.stdout
+}
+
+// Stderr returns a writer splitting the written bytes into lines written to
+// the multiplexer stderr. Incomplete lines are buffered until Close is called.
+func (s *Source) Stderr() io.Writer {
+	return s.stderr
+}
+
+// Close flushes the incomplete lines and closes the log file.
+func (s *Source) Close() error {
+	err := s.stdout.flush()
+	if ferr := s.stderr.flush(); err == nil {
+		err = ferr
+	}
+	if s.logFile != nil {
+		if cerr := s.logFile.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// lineWriter is an io.Writer calling write for each complete line.
+type lineWriter struct {
+	mu    sync.Mutex
+	buf   []byte
+	write func([]byte) error
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+		if err := w.write(trimCR(line)); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := w.buf
+	w.buf = nil
+	return w.write(trimCR(line))
+}
+
+// trimCR drops a terminal \r from a line.
+func trimCR(line []byte) []byte {
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		return line[:len(line)-1]
+	}
+	return line
+}
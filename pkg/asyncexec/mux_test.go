@@ -0,0 +1,136 @@
+package asyncexec_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+)
+
+func TestSource_WriteLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []asyncexec.MultiplexerOption
+		label      string
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "no label",
+			label:      "",
+			wantStdout: "hello\nworld\n",
+			wantStderr: "oops\n",
+		},
+		{
+			name:       "label",
+			label:      "s3",
+			wantStdout: "s3 | hello\ns3 | world\n",
+			wantStderr: "s3 | oops\n",
+		},
+		{
+			name:       "padded label",
+			opts:       []asyncexec.MultiplexerOption{asyncexec.WithLabelWidth(4)},
+			label:      "s3",
+			wantStdout: "s3   | hello\ns3   | world\n",
+			wantStderr: "s3   | oops\n",
+		},
+		{
+			name:       "coloured label",
+			opts:       []asyncexec.MultiplexerOption{asyncexec.WithColor(true)},
+			label:      "s3",
+			wantStdout: "\x1b[36ms3\x1b[0m | hello\n\x1b[36ms3\x1b[0m | world\n",
+			wantStderr: "\x1b[36ms3\x1b[0m | oops\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			m := asyncexec.NewMultiplexer(stdout, stderr, tt.opts...)
+			s, err := m.NewSource(tt.label)
+			require.NoError(t, err)
+
+			require.NoError(t, s.WriteStdout([]byte("hello")))
+			require.NoError(t, s.WriteStderr([]byte("oops")))
+			require.NoError(t, s.WriteStdout([]byte("world")))
+			require.NoError(t, s.Close())
+
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			assert.Equal(t, tt.wantStderr, stderr.String())
+		})
+	}
+}
+
+func TestSource_Stdout(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	m := asyncexec.NewMultiplexer(stdout, ioutil.Discard)
+	s, err := m.NewSource("s3")
+	require.NoError(t, err)
+
+	fmt.Fprint(s.Stdout(), "hel")
+	fmt.Fprint(s.Stdout(), "lo\r\nwor")
+	assert.Equal(t, "s3 | hello\n", stdout.String())
+
+	// incomplete lines are flushed on close
+	fmt.Fprint(s.Stdout(), "ld")
+	require.NoError(t, s.Close())
+	assert.Equal(t, "s3 | hello\ns3 | world\n", stdout.String())
+}
+
+func TestSource_ConcurrentWrites(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	m := asyncexec.NewMultiplexer(stdout, ioutil.Discard)
+
+	const sources, lines = 8, 200
+	var wg sync.WaitGroup
+	for i := 0; i < sources; i++ {
+		s, err := m.NewSource(fmt.Sprintf("source-%d", i))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(s *asyncexec.Source) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				s.WriteStdout([]byte(strings.Repeat("x", 100)))
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	output := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	require.Len(t, output, sources*lines)
+	for _, line := range output {
+		assert.Regexp(t, `^source-\d \| x{100}$`, line)
+	}
+}
+
+func TestSource_WithLogFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-asyncexec")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stdout := &bytes.Buffer{}
+	m := asyncexec.NewMultiplexer(stdout, stdout, asyncexec.WithColor(true))
+	logPath := filepath.Join(dir, "s3.log")
+	s, err := m.NewSource("s3", asyncexec.WithLogFile(logPath))
+	require.NoError(t, err)
+
+	require.NoError(t, s.WriteStdout([]byte("hello")))
+	require.NoError(t, s.WriteStderr([]byte("oops")))
+	require.NoError(t, s.Close())
+
+	// log files contain the raw lines, without label
+	b, err := ioutil.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\noops\n", string(b))
+
+	_, err = m.NewSource("s3", asyncexec.WithLogFile(filepath.Join(dir, "missing", "s3.log")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+// +build windows
+
+package asyncexec
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op, process groups can't be signaled on Windows.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup kills p, Windows doesn't support SIGTERM.
+func terminateProcessGroup(p *os.Process) {
+	_ = p.Kill()
+}
+
+// killProcessGroup kills p.
+func killProcessGroup(p *os.Process) {
+	_ = p.Kill()
+}
@@ -1,6 +1,8 @@
 package asyncexec
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,54 +11,28 @@ import (
 // StreamCommand executes a given command in a context directory and streams
 // the outputs to their according stdeout/stderr.
 func StreamCommand(workDir string, command string, args []string) error {
+	return StreamCommandContext(context.Background(), workDir, command, args)
+}
+
+// StreamCommandContext is like StreamCommand, but the command is stopped when
+// the context is done.
+func StreamCommandContext(ctx context.Context, workDir string, command string, args []string) error {
 	cmd := exec.Command(command, args...)
 	if workDir != "" {
 		cmd.Dir = workDir
 	}
 
-	acmd := New(cmd, 8)
-	err := acmd.Run()
-	if err != nil {
-		return err
-	}
-
-	// done is used to wait for stream readers to finish before exiting the function.
-	done := make(chan struct{})
-
-	go func() {
-		for b := range acmd.StdoutStream() {
-			_, err := os.Stdout.Write(b)
-			if err != nil {
-				msg := fmt.Sprintf("failed to write to Stdout: %v", err)
-				// should never happen, just panic.
-				panic(msg)
-			}
-		}
-		done <- struct{}{}
-	}()
-	go func() {
-		for b := range acmd.StderrStream() {
-			_, err := os.Stderr.Write(b)
-			if err != nil {
-				msg := fmt.Sprintf("failed to write to Stderr: %v", err)
-				// should never happen, just panic.
-				panic(msg)
-			}
-		}
-		done <- struct{}{}
-	}()
-
-	// wait for printers to finish
-	defer func() { _, _ = <-done, <-done }()
-
-	// wait for command to finish
-	err = acmd.Wait()
+	// lines are written without label, only their boundaries are restored.
+	source, err := NewMultiplexer(os.Stdout, os.Stderr).NewSource("")
 	if err != nil {
 		return err
 	}
+	defer source.Close()
 
-	if acmd.ExitCode() != 0 {
-		return fmt.Errorf("exited with code %d", acmd.ExitCode())
+	result, err := New(cmd, WithContext(ctx), WithOutput(source)).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exited with code %d", result.ExitCode)
 	}
-	return nil
+	return err
 }
@@ -0,0 +1,247 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package bump updates the ACK runtime version required by the service
+// controllers.
+package bump
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/controller"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// GenerateScript is the code-generator script generating a controller,
+	// relative to the code-generator repository.
+	GenerateScript = "scripts/build-controller.sh"
+	// sourcePathEnv is the variable setting the controller checkout generated
+	// by GenerateScript, which defaults to a sibling of the code-generator.
+	sourcePathEnv = "SERVICE_CONTROLLER_SOURCE_PATH"
+
+	branchPrefix = "bump-runtime-"
+)
+
+// Status is the outcome of a runtime bump.
+type Status string
+
+const (
+	// StatusChanged means the runtime was bumped and the changes committed.
+	StatusChanged Status = "CHANGED"
+	// StatusCurrent means the controller already requires the runtime version,
+	// or a newer one.
+	StatusCurrent Status = "CURRENT"
+	// StatusDirty means the repository was skipped because it has
+	// uncommitted changes.
+	StatusDirty Status = "SKIPPED (DIRTY)"
+	// StatusBranchExists means the repository was skipped because the bump
+	// branch already exists, for instance after a previous bump whose build
+	// failed.
+	StatusBranchExists Status = "SKIPPED (BRANCH EXISTS)"
+	// StatusBuildFailed means the controller doesn't build with the new
+	// runtime. The changes are left uncommitted in the bump branch.
+	StatusBuildFailed Status = "BUILD FAILED"
+	// StatusFailed means the bump failed before the controller was built.
+	StatusFailed Status = "FAILED"
+)
+
+// Result is the result of a runtime bump in a controller repository.
+type Result struct {
+	Status Status
+	// PreviousVersion is the runtime version required before the bump.
+	PreviousVersion string
+	// Branch is the branch containing the bump, if it was created.
+	Branch string
+	Err    error
+}
+
+// BranchName returns the name of the branch bumping the runtime to the given
+// version.
+func BranchName(version string) string {
+	return branchPrefix + version
+}
+
+// CommitMessage returns the message of the commit bumping the runtime to the
+// given version.
+func CommitMessage(version string) string {
+	return fmt.Sprintf("Update to ACK runtime `%s`", version)
+}
+
+// Option is a function modifying a Bumper.
+type Option func(*Bumper)
+
+// WithGoBinary sets the go binary invoked by the Bumper.
+func WithGoBinary(path string) Option {
+	return func(b *Bumper) {
+		b.goBinary = path
+	}
+}
+
+// WithGitBinary sets the git binary invoked by the Bumper.
+func WithGitBinary(path string) Option {
+	return func(b *Bumper) {
+		b.gitBinary = path
+	}
+}
+
+// WithOutput streams the output of the commands run by the Bumper to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(b *Bumper) {
+		b.out = out
+	}
+}
+
+// WithoutGenerate disables the code generation after the runtime update.
+func WithoutGenerate() Option {
+	return func(b *Bumper) {
+		b.generate = false
+	}
+}
+
+// Bumper updates the runtime requirement of controllers, regenerates them
+// using the local code-generator checkout and commits the changes in a new
+// branch.
+type Bumper struct {
+	codeGeneratorPath string
+	goBinary          string
+	gitBinary         string
+	generate          bool
+	out               asyncexec.Output
+}
+
+// NewBumper instantiates a new Bumper using the code-generator checkout found
+// at the given path.
+func NewBumper(codeGeneratorPath string, opts ...Option) *Bumper {
+	b := &Bumper{
+		codeGeneratorPath: codeGeneratorPath,
+		goBinary:          "go",
+		gitBinary:         "git",
+		generate:          true,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Bump updates the runtime requirement of a controller to the given version.
+// Repositories with uncommitted changes or an existing bump branch are
+// skipped, and the ones already requiring the version, or a newer one, are left
+// unchanged. Otherwise a new branch is created from the upstream default
+// branch, the go.mod file is updated and tidied, the controller regenerated
+// and built, and the changes are committed.
+func (b *Bumper) Bump(ctx context.Context, repo *repository.Repository, version string) *Result {
+	if !semver.IsValid(version) {
+		return &Result{Status: StatusFailed, Err: fmt.Errorf("%w: %q", semver.ErrInvalidVersion, version)}
+	}
+	// go.mod files and module queries use v prefixed versions
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	dirty, err := repo.IsDirty()
+	if err != nil {
+		return &Result{Status: StatusFailed, Err: err}
+	}
+	if dirty {
+		return &Result{Status: StatusDirty}
+	}
+	branch := BranchName(version)
+	exists, err := repo.BranchExists(branch)
+	if err != nil {
+		return &Result{Status: StatusFailed, Err: err}
+	}
+	if exists {
+		return &Result{Status: StatusBranchExists, Branch: branch}
+	}
+
+	result := &Result{}
+	fail := func(status Status, err error) *Result {
+		result.Status = status
+		result.Err = err
+		return result
+	}
+
+	git := tools.New(b.gitBinary).InDir(repo.FullPath)
+	goTool := tools.New(b.goBinary).InDir(repo.FullPath)
+	remote, defaultBranch := repo.UpstreamDefaultBranch()
+	err = git.Run(ctx, b.out, "fetch", remote, defaultBranch)
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	err = git.Run(ctx, b.out, "checkout", "-b", branch, remote+"/"+defaultBranch)
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	result.Branch = branch
+
+	versions, err := controller.ReadVersions(repo.FullPath)
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	result.PreviousVersion = versions.Runtime
+	if semver.IsValid(versions.Runtime) && semver.Compare(versions.Runtime, version) >= 0 {
+		// the branch is not needed, go back to the previous one
+		err = git.Run(ctx, b.out, "checkout", "-")
+		if err == nil {
+			err = git.Run(ctx, b.out, "branch", "--delete", branch)
+		}
+		if err != nil {
+			return fail(StatusFailed, err)
+		}
+		result.Branch = ""
+		result.Status = StatusCurrent
+		return result
+	}
+
+	err = goTool.Run(ctx, b.out, "get", controller.RuntimeModule+"@"+version)
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	err = goTool.Run(ctx, b.out, "mod", "tidy")
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	if b.generate {
+		script := tools.New(
+			filepath.Join(b.codeGeneratorPath, GenerateScript),
+			sourcePathEnv+"="+repo.FullPath,
+		).InDir(b.codeGeneratorPath)
+		err = script.Run(ctx, b.out, strings.TrimSuffix(repo.Name, "-controller"))
+		if err != nil {
+			return fail(StatusFailed, err)
+		}
+	}
+	err = goTool.Run(ctx, b.out, "build", "./...")
+	if err != nil {
+		return fail(StatusBuildFailed, err)
+	}
+
+	err = git.Run(ctx, b.out, "add", "--all")
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	err = git.Run(ctx, b.out, "commit", "--message", CommitMessage(version))
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+	result.Status = StatusChanged
+	return result
+}
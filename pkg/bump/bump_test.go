@@ -0,0 +1,197 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package bump
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/gomod"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+const goModContent = `module github.com/aws-controllers-k8s/s3-controller
+
+go 1.17
+
+require github.com/aws-controllers-k8s/runtime v0.14.0
+`
+
+// newControllerRepository creates and loads a controller repository, with a
+// committed go.mod file, in a temporary root directory.
+func newControllerRepository(t *testing.T) (*repository.Repository, string) {
+	rootDir, err := ioutil.TempDir("", "ackdev-bump")
+	require.NoError(t, err)
+
+	repoPath := filepath.Join(rootDir, "s3-controller")
+	gitRepo, err := testutil.NewGitRepository(repoPath, nil)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, gomod.FileName), []byte(goModContent), 0644))
+	w, err := gitRepo.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add(gomod.FileName)
+	require.NoError(t, err)
+	_, err = w.Commit("add go.mod", &git.CommitOptions{
+		Author: &object.Signature{Name: "ackdev", Email: "ackdev@example.com"},
+	})
+	require.NoError(t, err)
+
+	cfg := testutil.NewConfig("s3")
+	cfg.RootDirectory = rootDir
+	manager, err := repository.NewManager(cfg)
+	require.NoError(t, err)
+	repo, err := manager.LoadRepository("s3", repository.RepositoryTypeController)
+	require.NoError(t, err)
+	return repo, rootDir
+}
+
+// newBumper returns a Bumper invoking fake go, git and generation script
+// binaries, which are all logging their calls in binDir.
+func newBumper(t *testing.T, binDir, goBody string) *Bumper {
+	require.NoError(t, os.MkdirAll(binDir, os.ModePerm))
+	goBinary, err := testutil.NewFakeBinary(binDir, "go", goBody)
+	require.NoError(t, err)
+	gitBinary, err := testutil.NewFakeBinary(binDir, "git", "")
+	require.NoError(t, err)
+	codeGeneratorPath := filepath.Join(binDir, "code-generator")
+	require.NoError(t, os.MkdirAll(filepath.Join(codeGeneratorPath, "scripts"), os.ModePerm))
+	_, err = testutil.NewFakeBinary(filepath.Join(codeGeneratorPath, "scripts"), "build-controller.sh",
+		`echo "$SERVICE_CONTROLLER_SOURCE_PATH" > "$(dirname "$0")/source-path"`)
+	require.NoError(t, err)
+	return NewBumper(codeGeneratorPath, WithGoBinary(goBinary), WithGitBinary(gitBinary))
+}
+
+func TestBumper_Bump(t *testing.T) {
+	repo, rootDir := newControllerRepository(t)
+	defer os.RemoveAll(rootDir)
+	binDir := filepath.Join(rootDir, "bin")
+
+	// the v prefix is optional
+	result := newBumper(t, binDir, "").Bump(context.Background(), repo, "0.15.2")
+	require.NoError(t, result.Err)
+	assert.Equal(t, &Result{Status: StatusChanged, PreviousVersion: "v0.14.0", Branch: "bump-runtime-v0.15.2"}, result)
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "git")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"fetch upstream main",
+		"checkout -b bump-runtime-v0.15.2 upstream/main",
+		"add --all",
+		"commit --message Update to ACK runtime `v0.15.2`",
+	}, calls)
+	calls, err = testutil.FakeBinaryCalls(binDir, "go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"get github.com/aws-controllers-k8s/runtime@v0.15.2",
+		"mod tidy",
+		"build ./...",
+	}, calls)
+	calls, err = testutil.FakeBinaryCalls(filepath.Join(binDir, "code-generator", "scripts"), "build-controller.sh")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, calls)
+	// the controller checkout is regenerated, wherever it is
+	sourcePath, err := ioutil.ReadFile(filepath.Join(binDir, "code-generator", "scripts", "source-path"))
+	require.NoError(t, err)
+	assert.Equal(t, repo.FullPath+"\n", string(sourcePath))
+}
+
+func TestBumper_Bump_BuildFailed(t *testing.T) {
+	repo, rootDir := newControllerRepository(t)
+	defer os.RemoveAll(rootDir)
+	binDir := filepath.Join(rootDir, "bin")
+
+	goBody := `if [ "$1" = "build" ]; then
+  echo "undefined: ackrt.Foo" >&2
+  exit 2
+fi`
+	result := newBumper(t, binDir, goBody).Bump(context.Background(), repo, "v0.15.2")
+	assert.Equal(t, StatusBuildFailed, result.Status)
+	assert.Equal(t, "bump-runtime-v0.15.2", result.Branch)
+	require.Error(t, result.Err)
+	assert.Contains(t, result.Err.Error(), "undefined: ackrt.Foo")
+
+	// the changes are not committed
+	calls, err := testutil.FakeBinaryCalls(binDir, "git")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch upstream main", "checkout -b bump-runtime-v0.15.2 upstream/main"}, calls)
+}
+
+func TestBumper_Bump_Skipped(t *testing.T) {
+	repo, rootDir := newControllerRepository(t)
+	defer os.RemoveAll(rootDir)
+	binDir := filepath.Join(rootDir, "bin")
+	bumper := newBumper(t, binDir, "")
+
+	result := bumper.Bump(context.Background(), repo, "latest")
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Error(t, result.Err)
+
+	// an existing bump branch is not reused
+	gitRepo, err := git.PlainOpen(repo.FullPath)
+	require.NoError(t, err)
+	head, err := gitRepo.Head()
+	require.NoError(t, err)
+	require.NoError(t, gitRepo.Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewBranchReferenceName("bump-runtime-v0.15.2"), head.Hash(),
+	)))
+	result = bumper.Bump(context.Background(), repo, "v0.15.2")
+	assert.Equal(t, &Result{Status: StatusBranchExists, Branch: "bump-runtime-v0.15.2"}, result)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repo.FullPath, "new.go"), []byte("package main"), 0644))
+	result = bumper.Bump(context.Background(), repo, "v0.16.0")
+	assert.Equal(t, &Result{Status: StatusDirty}, result)
+
+	// no command was run
+	_, err = testutil.FakeBinaryCalls(binDir, "git")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestBumper_Bump_Current(t *testing.T) {
+	repo, rootDir := newControllerRepository(t)
+	defer os.RemoveAll(rootDir)
+	binDir := filepath.Join(rootDir, "bin")
+
+	result := newBumper(t, binDir, "").Bump(context.Background(), repo, "v0.14.0")
+	assert.Equal(t, &Result{Status: StatusCurrent, PreviousVersion: "v0.14.0"}, result)
+
+	// the branch created from upstream is deleted
+	calls, err := testutil.FakeBinaryCalls(binDir, "git")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"fetch upstream main",
+		"checkout -b bump-runtime-v0.14.0 upstream/main",
+		"checkout -",
+		"branch --delete bump-runtime-v0.14.0",
+	}, calls)
+	_, err = testutil.FakeBinaryCalls(binDir, "go")
+	assert.True(t, os.IsNotExist(err))
+
+	// controllers requiring a newer runtime are not downgraded
+	result = newBumper(t, binDir, "").Bump(context.Background(), repo, "v0.13.1")
+	assert.Equal(t, &Result{Status: StatusCurrent, PreviousVersion: "v0.14.0"}, result)
+	_, err = testutil.FakeBinaryCalls(binDir, "go")
+	assert.True(t, os.IsNotExist(err))
+}
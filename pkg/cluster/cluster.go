@@ -0,0 +1,414 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// ackCRDGroupSuffix is the suffix of the API groups of the ACK custom
+	// resources.
+	ackCRDGroupSuffix = "services.k8s.aws"
+
+	kindConfigFileName = "kind-config.yaml"
+	kubeconfigFileName = "kubeconfig"
+
+	defaultReadyTimeout = 5 * time.Minute
+)
+
+var (
+	ErrClusterNotFound error = errors.New("cluster not found")
+
+	// kindConfigTemplate is the template of the kind cluster configuration.
+	kindConfigTemplate = template.Must(template.New("kind-config").Parse(`kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+{{- if .NodeImage }}
+  image: {{ .NodeImage }}
+{{- end }}
+{{- range $i := .WorkerNodes }}
+- role: worker
+{{- if $.NodeImage }}
+  image: {{ $.NodeImage }}
+{{- end }}
+{{- end }}
+`))
+)
+
+// Option is a function modifying a Manager.
+type Option func(*Manager)
+
+// WithKindBinary sets the kind binary invoked by the Manager.
+func WithKindBinary(path string) Option {
+	return func(m *Manager) {
+		m.kind.Binary = path
+	}
+}
+
+// WithKubectlBinary sets the kubectl binary invoked by the Manager.
+func WithKubectlBinary(path string) Option {
+	return func(m *Manager) {
+		m.kubectlBinary = path
+	}
+}
+
+// WithOutput streams the output of the kind and kubectl commands to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(m *Manager) {
+		m.out = out
+	}
+}
+
+// WithReadyTimeout sets the time waited for the cluster nodes to be ready.
+func WithReadyTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		m.readyTimeout = d
+	}
+}
+
+// Manager manages the lifecycle of a local kind cluster. The files generated
+// for the cluster are stored in <stateDirectory>/clusters/<name>.
+type Manager struct {
+	cfg      config.ClusterConfig
+	stateDir string
+
+	kind          *tools.Tool
+	kubectlBinary string
+	out           asyncexec.Output
+	readyTimeout  time.Duration
+}
+
+// NewManager instantiates a new cluster Manager.
+func NewManager(cfg config.ClusterConfig, stateDirectory string, opts ...Option) *Manager {
+	m := &Manager{
+		cfg:           cfg,
+		stateDir:      filepath.Join(stateDirectory, "clusters", cfg.Name),
+		kind:          tools.New("kind"),
+		kubectlBinary: "kubectl",
+		readyTimeout:  defaultReadyTimeout,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Name returns the cluster name.
+func (m *Manager) Name() string {
+	return m.cfg.Name
+}
+
+// KubeconfigPath returns the path of the kubeconfig exported for the cluster.
+func (m *Manager) KubeconfigPath() string {
+	return filepath.Join(m.stateDir, kubeconfigFileName)
+}
+
+// Kubectl returns a kubectl tool using the cluster kubeconfig.
+func (m *Manager) Kubectl() *tools.Tool {
+	return tools.New(m.kubectlBinary, "KUBECONFIG="+m.KubeconfigPath())
+}
+
+// Exists returns true if the kind cluster exists.
+func (m *Manager) Exists(ctx context.Context) (bool, error) {
+	b, err := m.kind.Output(ctx, "get", "clusters")
+	if err != nil {
+		return false, err
+	}
+	for _, name := range strings.Fields(string(b)) {
+		if name == m.cfg.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ID returns an identifier of the running cluster which, unlike its name,
+// changes when the cluster is recreated: the UID of the kube-system namespace.
+// The cluster kubeconfig must have been exported, see EnsureRunning.
+func (m *Manager) ID(ctx context.Context) (string, error) {
+	b, err := m.Kubectl().Output(ctx, "get", "namespace", "kube-system", "-o", "jsonpath={.metadata.uid}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Up creates the kind cluster if it doesn't exist, waits for its nodes to be
+// ready and exports its kubeconfig.
+func (m *Manager) Up(ctx context.Context) error {
+	exists, err := m.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(m.stateDir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		err = m.exportKubeconfig(ctx, m.out)
+	} else {
+		kindConfigPath := filepath.Join(m.stateDir, kindConfigFileName)
+		err = m.writeKindConfig(kindConfigPath)
+		if err != nil {
+			return err
+		}
+		err = m.kind.Run(ctx, m.out,
+			"create", "cluster",
+			"--name", m.cfg.Name,
+			"--config", kindConfigPath,
+			"--kubeconfig", m.KubeconfigPath(),
+			"--wait", m.readyTimeout.String(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	return m.Kubectl().Run(ctx, m.out,
+		"wait", "--for=condition=Ready", "nodes", "--all",
+		"--timeout", m.readyTimeout.String(),
+	)
+}
+
+// EnsureRunning returns ErrClusterNotFound if the cluster doesn't exist,
+// otherwise it exports the cluster kubeconfig.
+func (m *Manager) EnsureRunning(ctx context.Context) error {
+	exists, err := m.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrClusterNotFound, m.cfg.Name)
+	}
+	return m.exportKubeconfig(ctx, nil)
+}
+
+// exportKubeconfig writes the cluster kubeconfig to KubeconfigPath.
+func (m *Manager) exportKubeconfig(ctx context.Context, out asyncexec.Output) error {
+	err := os.MkdirAll(m.stateDir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return m.kind.Run(ctx, out,
+		"export", "kubeconfig",
+		"--name", m.cfg.Name,
+		"--kubeconfig", m.KubeconfigPath(),
+	)
+}
+
+// LoadImage loads a local docker image into the cluster nodes.
+func (m *Manager) LoadImage(ctx context.Context, image string) error {
+	return m.kind.Run(ctx, m.out,
+		"load", "docker-image", image,
+		"--name", m.cfg.Name,
+	)
+}
+
+// writeKindConfig renders the kind cluster configuration.
+func (m *Manager) writeKindConfig(path string) error {
+	var buf bytes.Buffer
+	err := kindConfigTemplate.Execute(&buf, struct {
+		NodeImage   string
+		WorkerNodes []int
+	}{
+		NodeImage:   m.cfg.NodeImage,
+		WorkerNodes: make([]int, m.cfg.Workers),
+	})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Down deletes the kind cluster and the files generated for it.
+func (m *Manager) Down(ctx context.Context) error {
+	exists, err := m.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err = m.kind.Run(ctx, m.out,
+			"delete", "cluster",
+			"--name", m.cfg.Name,
+			"--kubeconfig", m.KubeconfigPath(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(m.stateDir)
+}
+
+// Node describes a cluster node.
+type Node struct {
+	Name    string
+	Roles   []string
+	Ready   bool
+	Version string
+}
+
+// CRD describes an ACK custom resource definition installed in the cluster.
+type CRD struct {
+	Name     string
+	Group    string
+	Versions []string
+}
+
+// Status describes the state of the cluster.
+type Status struct {
+	Name           string
+	KubeconfigPath string
+	Nodes          []Node
+	CRDs           []CRD
+}
+
+// Status returns the cluster nodes and the ACK custom resource definitions
+// installed in the cluster. It returns ErrClusterNotFound if the cluster
+// doesn't exist.
+func (m *Manager) Status(ctx context.Context) (*Status, error) {
+	// the kubeconfig might have been removed or be outdated
+	err := m.EnsureRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := m.nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	crds, err := m.crds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{
+		Name:           m.cfg.Name,
+		KubeconfigPath: m.KubeconfigPath(),
+		Nodes:          nodes,
+		CRDs:           crds,
+	}, nil
+}
+
+// nodeList is the subset of a kubectl node list used by ackdev.
+type nodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			NodeInfo struct {
+				KubeletVersion string `json:"kubeletVersion"`
+			} `json:"nodeInfo"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func (m *Manager) nodes(ctx context.Context) ([]Node, error) {
+	b, err := m.Kubectl().Output(ctx, "get", "nodes", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list nodeList
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("cannot parse nodes: %v", err)
+	}
+
+	nodes := make([]Node, 0, len(list.Items))
+	for _, item := range list.Items {
+		node := Node{
+			Name:    item.Metadata.Name,
+			Roles:   []string{},
+			Version: item.Status.NodeInfo.KubeletVersion,
+		}
+		for label := range item.Metadata.Labels {
+			if strings.HasPrefix(label, "node-role.kubernetes.io/") {
+				node.Roles = append(node.Roles, strings.TrimPrefix(label, "node-role.kubernetes.io/"))
+			}
+		}
+		sort.Strings(node.Roles)
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == "Ready" {
+				node.Ready = condition.Status == "True"
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// crdList is the subset of a kubectl custom resource definition list used by
+// ackdev.
+type crdList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Group    string `json:"group"`
+			Versions []struct {
+				Name string `json:"name"`
+			} `json:"versions"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+func (m *Manager) crds(ctx context.Context) ([]CRD, error) {
+	b, err := m.Kubectl().Output(ctx, "get", "customresourcedefinitions", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list crdList
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("cannot parse custom resource definitions: %v", err)
+	}
+
+	crds := []CRD{}
+	for _, item := range list.Items {
+		if !strings.HasSuffix(item.Spec.Group, ackCRDGroupSuffix) {
+			continue
+		}
+		crd := CRD{
+			Name:     item.Metadata.Name,
+			Group:    item.Spec.Group,
+			Versions: []string{},
+		}
+		for _, version := range item.Spec.Versions {
+			crd.Versions = append(crd.Versions, version.Name)
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
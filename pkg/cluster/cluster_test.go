@@ -0,0 +1,194 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+const (
+	// fakeKind keeps the list of clusters in a 'clusters' file next to it.
+	fakeKind = `dir=$(dirname "$0")
+touch "$dir/clusters"
+case "$1 $2" in
+"get clusters") cat "$dir/clusters" ;;
+"create cluster") echo "$4" >> "$dir/clusters"; echo "creating cluster" >&2 ;;
+"delete cluster") grep -v "^$4\$" "$dir/clusters" > "$dir/clusters.tmp"; mv "$dir/clusters.tmp" "$dir/clusters" ;;
+esac`
+
+	// fakeKubectl prints the nodes, CRDs and kube-system namespace UID of a
+	// cluster.
+	fakeKubectl = `echo "KUBECONFIG=$KUBECONFIG" >> "$(dirname "$0")/kubectl.env"
+case "$1 $2" in
+"get nodes") cat <<EOF
+{"items": [
+  {"metadata": {"name": "ack-control-plane", "labels": {"node-role.kubernetes.io/master": "", "node-role.kubernetes.io/control-plane": ""}},
+   "status": {"conditions": [{"type": "Ready", "status": "True"}], "nodeInfo": {"kubeletVersion": "v1.21.1"}}},
+  {"metadata": {"name": "ack-worker"},
+   "status": {"conditions": [{"type": "Ready", "status": "False"}], "nodeInfo": {"kubeletVersion": "v1.21.1"}}}
+]}
+EOF
+;;
+"get customresourcedefinitions") cat <<EOF
+{"items": [
+  {"metadata": {"name": "buckets.s3.services.k8s.aws"}, "spec": {"group": "s3.services.k8s.aws", "versions": [{"name": "v1alpha1"}]}},
+  {"metadata": {"name": "certificates.cert-manager.io"}, "spec": {"group": "cert-manager.io", "versions": [{"name": "v1"}]}}
+]}
+EOF
+;;
+"get namespace") echo "9f3c2a1e-6b1d-4c1e-8e0a-3f2d1c0b9a87" ;;
+esac`
+)
+
+func newTestManager(t *testing.T, cfg config.ClusterConfig) (*Manager, string, func()) {
+	dir, err := ioutil.TempDir("", "ackdev-cluster")
+	require.NoError(t, err)
+
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, os.ModePerm))
+	kind, err := testutil.NewFakeBinary(binDir, "kind", fakeKind)
+	require.NoError(t, err)
+	kubectl, err := testutil.NewFakeBinary(binDir, "kubectl", fakeKubectl)
+	require.NoError(t, err)
+
+	m := NewManager(cfg, filepath.Join(dir, "state"), WithKindBinary(kind), WithKubectlBinary(kubectl))
+	return m, binDir, func() { os.RemoveAll(dir) }
+}
+
+func TestManager_Up(t *testing.T) {
+	m, binDir, cleanup := newTestManager(t, config.ClusterConfig{Name: "ack", NodeImage: "kindest/node:v1.21.1", Workers: 2})
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, m.Up(ctx))
+
+	kindConfigPath := filepath.Join(m.stateDir, kindConfigFileName)
+	calls, err := testutil.FakeBinaryCalls(binDir, "kind")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"get clusters",
+		"create cluster --name ack --config " + kindConfigPath + " --kubeconfig " + m.KubeconfigPath() + " --wait 5m0s",
+	}, calls)
+
+	b, err := ioutil.ReadFile(kindConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+  image: kindest/node:v1.21.1
+- role: worker
+  image: kindest/node:v1.21.1
+- role: worker
+  image: kindest/node:v1.21.1
+`, string(b))
+
+	calls, err = testutil.FakeBinaryCalls(binDir, "kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wait --for=condition=Ready nodes --all --timeout 5m0s"}, calls)
+	env, err := ioutil.ReadFile(filepath.Join(binDir, "kubectl.env"))
+	require.NoError(t, err)
+	assert.Equal(t, "KUBECONFIG="+m.KubeconfigPath()+"\n", string(env))
+
+	// existing clusters are not recreated
+	require.NoError(t, m.Up(ctx))
+	calls, err = testutil.FakeBinaryCalls(binDir, "kind")
+	require.NoError(t, err)
+	assert.Equal(t, "export kubeconfig --name ack --kubeconfig "+m.KubeconfigPath(), calls[len(calls)-1])
+}
+
+func TestManager_Down(t *testing.T) {
+	m, binDir, cleanup := newTestManager(t, config.ClusterConfig{Name: "ack"})
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, m.Up(ctx))
+	require.NoError(t, m.Down(ctx))
+
+	exists, err := m.Exists(ctx)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = os.Stat(m.stateDir)
+	assert.True(t, os.IsNotExist(err))
+
+	// deleting a missing cluster is a no-op
+	require.NoError(t, m.Down(ctx))
+	calls, err := testutil.FakeBinaryCalls(binDir, "kind")
+	require.NoError(t, err)
+	assert.Equal(t, "get clusters", calls[len(calls)-1])
+}
+
+func TestManager_Status(t *testing.T) {
+	m, _, cleanup := newTestManager(t, config.ClusterConfig{Name: "ack"})
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := m.Status(ctx)
+	assert.True(t, errors.Is(err, ErrClusterNotFound))
+
+	require.NoError(t, m.Up(ctx))
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Status{
+		Name:           "ack",
+		KubeconfigPath: m.KubeconfigPath(),
+		Nodes: []Node{
+			{Name: "ack-control-plane", Roles: []string{"control-plane", "master"}, Ready: true, Version: "v1.21.1"},
+			{Name: "ack-worker", Roles: []string{}, Ready: false, Version: "v1.21.1"},
+		},
+		CRDs: []CRD{
+			{Name: "buckets.s3.services.k8s.aws", Group: "s3.services.k8s.aws", Versions: []string{"v1alpha1"}},
+		},
+	}, status)
+}
+
+func TestManager_ID(t *testing.T) {
+	m, binDir, cleanup := newTestManager(t, config.ClusterConfig{Name: "ack"})
+	defer cleanup()
+
+	id, err := m.ID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "9f3c2a1e-6b1d-4c1e-8e0a-3f2d1c0b9a87", id)
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"get namespace kube-system -o jsonpath={.metadata.uid}"}, calls)
+}
+
+func TestManager_kindError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-cluster")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	kind, err := testutil.NewFakeBinary(dir, "kind", `echo "docker is not running" >&2; exit 1`)
+	require.NoError(t, err)
+
+	m := NewManager(config.ClusterConfig{Name: "ack"}, dir, WithKindBinary(kind))
+	err = m.Up(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "docker is not running")
+}
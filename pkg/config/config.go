@@ -14,17 +14,27 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 
 	"github.com/ghodss/yaml"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
 )
 
+// RepositoryTypes are the supported values of RepositoryConfig.Type
+var RepositoryTypes = []string{"core", "controller", "tooling"}
+
 // Config is the ackdev global configuration. It contains information and default values
 // used by ackdev to manage local repositories, forks, dependencies, controllers...
 type Config struct {
 	// RootDirectory is the parent directory of the all ACK local repositories.
 	// If it's not specified ackdev will use $GOPATH/src/github.com/aws-controllers-k8s
 	RootDirectory string `yaml:"rootDirectory" json:"rootDirectory"`
+	// StateDirectory is the directory where ackdev stores the files it generates,
+	// such as cluster kubeconfigs. If it's not specified ackdev will use $HOME/.ackdev
+	StateDirectory string `yaml:"stateDirectory,omitempty" json:"stateDirectory,omitempty"`
 	// Git contains information used by ackdev to manage local git repositories.
 	Git GitConfig `yaml:"git" json:"git"`
 	// Github contains information used by ackdev to manage Github forks.
@@ -36,15 +46,127 @@ type Config struct {
 	// RunConfig let specify the arguments and flags used to run a controller locally,
 	// without having to build it image or deploy it into a cluster.
 	RunConfig RunConfig `yaml:"run" json:"run"`
+	// Cluster contains the configuration of the local kind cluster used to deploy
+	// and test controllers.
+	Cluster ClusterConfig `yaml:"cluster" json:"cluster"`
+	// Dependencies declares development tools listed by `ackdev list deps` in
+	// addition to the built-in ones. A dependency with the name of a built-in
+	// one replaces it.
+	Dependencies []DependencyConfig `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
 }
 
 // RepositoriesConfig represent repositories that are be managed by ackdev.
 type RepositoriesConfig struct {
 	// Core is the list of ACK core repositories. The default configuration contains:
 	// commmunity, code-generator, test-infra, dev-tools and runtime.
-	Core []string `yaml:"core" json:"core"`
+	Core []RepositoryConfig `yaml:"core" json:"core"`
 	// Services is the list of service controllers managed by ackdev.
-	Services []string `yaml:"services" json:"services"`
+	Services []RepositoryConfig `yaml:"services" json:"services"`
+	// Tooling is the list of tooling repositories managed by ackdev. For example
+	// forks of helper tools used by the ACK test infrastructure.
+	Tooling []RepositoryConfig `yaml:"tooling,omitempty" json:"tooling,omitempty"`
+}
+
+// ByType returns the list of configured repositories of a given type (core,
+// controller or tooling).
+func (rc *RepositoriesConfig) ByType(t string) (*[]RepositoryConfig, error) {
+	switch t {
+	case "core":
+		return &rc.Core, nil
+	case "controller":
+		return &rc.Services, nil
+	case "tooling":
+		return &rc.Tooling, nil
+	default:
+		return nil, fmt.Errorf("unsupported repository type: %s", t)
+	}
+}
+
+// RepositoryConfig represents a repository managed by ackdev. By default
+// repositories follow the ackdev conventions: they are cloned in RootDirectory/<name>,
+// forked with the configured fork prefix and have aws-controllers-k8s as upstream
+// owner. In the configuration file a repository can either be a plain name, or
+// an object overriding some of these conventions.
+type RepositoryConfig struct {
+	// Name is the repository name. For service controllers it is the service name.
+	Name string `yaml:"name" json:"name"`
+	// Path is the local path of the repository. Relative paths are relative to
+	// the root directory.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// ForkName is the name of the personal fork of the repository.
+	ForkName string `yaml:"forkName,omitempty" json:"forkName,omitempty"`
+	// UpstreamOwner is the Github owner of the upstream repository.
+	UpstreamOwner string `yaml:"upstreamOwner,omitempty" json:"upstreamOwner,omitempty"`
+	// DefaultBranch is the default branch of the upstream repository.
+	DefaultBranch string `yaml:"defaultBranch,omitempty" json:"defaultBranch,omitempty"`
+	// Type overrides the repository type (core, controller or tooling).
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+}
+
+// repositoryConfig is used to (un)marshal RepositoryConfig objects without
+// recursing into their custom (un)marshalers.
+type repositoryConfig RepositoryConfig
+
+// UnmarshalJSON accepts both the plain string form and the object form of a
+// repository configuration.
+func (rc *RepositoryConfig) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*rc = RepositoryConfig{Name: name}
+		return nil
+	}
+	return json.Unmarshal(b, (*repositoryConfig)(rc))
+}
+
+// MarshalJSON marshals a repository configuration to a plain string if it
+// doesn't override any of the ackdev conventions.
+func (rc RepositoryConfig) MarshalJSON() ([]byte, error) {
+	if rc == (RepositoryConfig{Name: rc.Name}) {
+		return json.Marshal(rc.Name)
+	}
+	return json.Marshal(repositoryConfig(rc))
+}
+
+// FindRepository returns the configuration of the repository with the given
+// name, or nil if it is not part of the list.
+func FindRepository(repos []RepositoryConfig, name string) *RepositoryConfig {
+	for i := range repos {
+		if repos[i].Name == name {
+			return &repos[i]
+		}
+	}
+	return nil
+}
+
+// RemoveRepository returns the list of repositories without the repository
+// with the given name.
+func RemoveRepository(repos []RepositoryConfig, name string) []RepositoryConfig {
+	kept := make([]RepositoryConfig, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Name != name {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// RepositoryNames returns the names of a list of repositories.
+func RepositoryNames(repos []RepositoryConfig) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+// NewRepositoryConfigs returns a list of repositories following the ackdev
+// conventions.
+func NewRepositoryConfigs(names ...string) []RepositoryConfig {
+	repos := make([]RepositoryConfig, 0, len(names))
+	for _, name := range names {
+		repos = append(repos, RepositoryConfig{Name: name})
+	}
+	return repos
 }
 
 // GithubConfig represents the Github information needed to personal forks.
@@ -67,28 +189,34 @@ type GitConfig struct {
 	SSHKeyPath string `yaml:"sshKeyPath" json:"sshKeyPath"`
 }
 
-// RunConfig contains flags and arguments passed to service controllers binaries when
-// they are executed locally.
-type RunConfig struct {
-	// Flags is the map of flags/values passed to the controller binaries. For example
-	// to pass --aws-region=us-west-1 you'll need to set Flags to {"aws-region","us-west-1"}
-	Flags map[string]string `yaml:"flags" json:"flags"`
+// ClusterConfig contains the configuration of the local kind cluster.
+type ClusterConfig struct {
+	// Name is the kind cluster name. The default name is 'ack'.
+	Name string `yaml:"name" json:"name"`
+	// NodeImage is the kind node image, which determines the Kubernetes version.
+	// If it's not specified kind uses its default node image.
+	NodeImage string `yaml:"nodeImage,omitempty" json:"nodeImage,omitempty"`
+	// Workers is the number of worker nodes created alongside the control plane.
+	Workers int `yaml:"workers,omitempty" json:"workers,omitempty"`
 }
 
 // DefaultConfig is the default configuration used to generated ackdev config
 var DefaultConfig = Config{
 	Repositories: RepositoriesConfig{
-		Core: []string{
+		Core: NewRepositoryConfigs(
 			"runtime",
 			"dev-tools",
 			"community",
 			"code-generator",
 			"test-infra",
-		},
+		),
 	},
 	Github: GithubConfig{
 		ForkPrefix: "ack-",
 	},
+	Cluster: ClusterConfig{
+		Name: "ack",
+	},
 }
 
 // Load reads a local configuration file and returns an ackdev configuration object.
@@ -99,13 +227,49 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	cfg := DefaultConfig
+	// copy the default repositories, so that unmarshaling and migrating the
+	// configuration never modifies DefaultConfig.
+	cfg.Repositories.Core = append([]RepositoryConfig{}, DefaultConfig.Repositories.Core...)
 	err = yaml.Unmarshal(content, &cfg)
 	if err != nil {
 		return nil, err
 	}
+	migrateRunFlags(&cfg.RunConfig)
+	if cfg.Cluster.Name == "" {
+		cfg.Cluster.Name = DefaultConfig.Cluster.Name
+	}
+
+	err = validate(&cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
+// validate checks that a configuration object doesn't contain invalid values.
+func validate(cfg *Config) error {
+	repos := []RepositoryConfig{}
+	repos = append(repos, cfg.Repositories.Core...)
+	repos = append(repos, cfg.Repositories.Services...)
+	repos = append(repos, cfg.Repositories.Tooling...)
+	for _, repo := range repos {
+		if repo.Name == "" {
+			return fmt.Errorf("invalid repository configuration: missing name")
+		}
+		if repo.Type != "" && !util.InStrings(repo.Type, RepositoryTypes) {
+			return fmt.Errorf("invalid repository configuration %s: unsupported type %s", repo.Name, repo.Type)
+		}
+	}
+	if cfg.Cluster.Workers < 0 {
+		return fmt.Errorf("invalid cluster configuration: negative number of workers")
+	}
+	err := validateDependencies(cfg.Dependencies)
+	if err != nil {
+		return err
+	}
+	return validateRunConfig(&cfg.RunConfig)
+}
+
 // Save serialise a configuration object and writes it to given filepath.
 func Save(cfg *Config, filename string) error {
 	bytes, err := yaml.Marshal(cfg)
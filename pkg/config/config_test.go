@@ -0,0 +1,258 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes a configuration file in a temporary directory and returns
+// its path.
+func writeConfig(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "ackdev-config")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "ackdev.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantCore     []RepositoryConfig
+		wantServices []RepositoryConfig
+		wantErr      bool
+	}{
+		{
+			name: "plain string form",
+			content: `
+repositories:
+  core:
+  - runtime
+  services:
+  - s3
+  - ecr
+`,
+			wantCore:     NewRepositoryConfigs("runtime"),
+			wantServices: NewRepositoryConfigs("s3", "ecr"),
+		},
+		{
+			name: "mixed string and object forms",
+			content: `
+repositories:
+  services:
+  - s3
+  - name: ecr
+    path: /src/ecr
+    forkName: my-ecr-fork
+    upstreamOwner: someone
+    defaultBranch: dev
+  - name: eks
+    type: tooling
+`,
+			wantCore: DefaultConfig.Repositories.Core,
+			wantServices: []RepositoryConfig{
+				{Name: "s3"},
+				{
+					Name:          "ecr",
+					Path:          "/src/ecr",
+					ForkName:      "my-ecr-fork",
+					UpstreamOwner: "someone",
+					DefaultBranch: "dev",
+				},
+				{Name: "eks", Type: "tooling"},
+			},
+		},
+		{
+			name: "unsupported repository type",
+			content: `
+repositories:
+  services:
+  - name: s3
+    type: library
+`,
+			wantErr: true,
+		},
+		{
+			name: "missing repository name",
+			content: `
+repositories:
+  services:
+  - path: /src/s3
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			defer os.RemoveAll(filepath.Dir(path))
+
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.wantCore, cfg.Repositories.Core)
+			assert.Equal(t, tt.wantServices, cfg.Repositories.Services)
+		})
+	}
+}
+
+func TestRepositoryConfig_MarshalJSON(t *testing.T) {
+	repos := []RepositoryConfig{
+		{Name: "s3"},
+		{Name: "ecr", Path: "/src/ecr"},
+	}
+	b, err := yaml.Marshal(repos)
+	require.NoError(t, err)
+	assert.Equal(t, "- s3\n- name: ecr\n  path: /src/ecr\n", string(b))
+}
+
+func TestLoad_Emulator(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *EmulatorConfig
+		wantErr bool
+	}{
+		{
+			name:    "no emulator",
+			content: "run:\n  flags: {}\n",
+		},
+		{
+			name: "container emulator",
+			content: `
+run:
+  emulator:
+    image: localstack/localstack
+    port: 4566
+    healthPath: /_localstack/health
+    env:
+      SERVICES: s3
+`,
+			want: &EmulatorConfig{
+				Image:      "localstack/localstack",
+				Port:       4566,
+				HealthPath: "/_localstack/health",
+				Env:        map[string]string{"SERVICES": "s3"},
+			},
+		},
+		{
+			name: "process emulator",
+			content: `
+run:
+  emulator:
+    command: [moto_server, -p, "5000"]
+    port: 5000
+`,
+			want: &EmulatorConfig{Command: []string{"moto_server", "-p", "5000"}, Port: 5000},
+		},
+		{
+			name:    "image and command",
+			content: "run:\n  emulator:\n    image: motoserver/moto\n    command: [moto_server]\n    port: 5000\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			content: "run:\n  emulator:\n    image: motoserver/moto\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			defer os.RemoveAll(filepath.Dir(path))
+
+			cfg, err := Load(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.RunConfig.Emulator)
+		})
+	}
+}
+
+func TestLoad_Dependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []DependencyConfig
+		wantErr bool
+	}{
+		{
+			name: "custom dependencies",
+			content: `
+dependencies:
+- name: yq
+  versionArgs: [--version]
+  versionRegex: 'version v?([0-9.]+)'
+  minVersion: 4.0.0
+- name: pytest
+  versionArgs: [--version]
+  optional: true
+`,
+			want: []DependencyConfig{
+				{Name: "yq", VersionArgs: []string{"--version"}, VersionRegex: "version v?([0-9.]+)", MinVersion: "4.0.0"},
+				{Name: "pytest", VersionArgs: []string{"--version"}, Optional: true},
+			},
+		},
+		{
+			name:    "missing name",
+			content: "dependencies:\n- versionArgs: [--version]\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicated name",
+			content: "dependencies:\n- name: yq\n- name: yq\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version regex",
+			content: "dependencies:\n- name: yq\n  versionRegex: 'version ([0-9.]+'\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid minimum version",
+			content: "dependencies:\n- name: yq\n  minVersion: latest\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			defer os.RemoveAll(filepath.Dir(path))
+
+			cfg, err := Load(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Dependencies)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+// DependencyConfig declares a development tool that isn't part of the ackdev
+// built-in dependencies, for example yq or golangci-lint.
+type DependencyConfig struct {
+	// Name is the binary name of the tool.
+	Name string `yaml:"name" json:"name"`
+	// VersionArgs are the arguments passed to the binary to print its version.
+	VersionArgs []string `yaml:"versionArgs,omitempty" json:"versionArgs,omitempty"`
+	// VersionRegex extracts the version from the output of the version
+	// command. The first capture group is used if the expression has one,
+	// otherwise the whole match. If it's not specified ackdev uses the first
+	// semantic version found in the output.
+	VersionRegex string `yaml:"versionRegex,omitempty" json:"versionRegex,omitempty"`
+	// MinVersion is the oldest supported version of the tool.
+	MinVersion string `yaml:"minVersion,omitempty" json:"minVersion,omitempty"`
+	// Optional tools are reported as such when they are not installed.
+	Optional bool `yaml:"optional,omitempty" json:"optional,omitempty"`
+}
+
+// validateDependencies checks the dependencies declared in the configuration.
+func validateDependencies(dependencies []DependencyConfig) error {
+	names := map[string]bool{}
+	for _, dependency := range dependencies {
+		if dependency.Name == "" {
+			return fmt.Errorf("invalid dependency configuration: missing name")
+		}
+		if names[dependency.Name] {
+			return fmt.Errorf("invalid dependency configuration %s: duplicated name", dependency.Name)
+		}
+		names[dependency.Name] = true
+		if dependency.VersionRegex != "" {
+			_, err := regexp.Compile(dependency.VersionRegex)
+			if err != nil {
+				return fmt.Errorf("invalid dependency configuration %s: invalid version regex: %v", dependency.Name, err)
+			}
+		}
+		if dependency.MinVersion != "" && !semver.IsValid(dependency.MinVersion) {
+			return fmt.Errorf("invalid dependency configuration %s: invalid minimum version %q", dependency.Name, dependency.MinVersion)
+		}
+	}
+	return nil
+}
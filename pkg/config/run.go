@@ -0,0 +1,222 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultRunProfile is the profile used when no profile is selected.
+	DefaultRunProfile = "default"
+
+	logLevelFlag = "log-level"
+)
+
+// RunConfig contains the configurations used to run service controllers
+// locally.
+type RunConfig struct {
+	// Flags is the map of flags/values passed to the controller binaries.
+	//
+	// Deprecated: Flags is only kept to load older configuration files, it is
+	// replaced by the flags of the default profile.
+	Flags map[string]string `yaml:"flags,omitempty" json:"flags,omitempty"`
+	// Profiles are named run configurations, selected with `ackdev run
+	// --profile`. The default profile is used when no profile is selected.
+	Profiles map[string]RunProfile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	// Emulator is the local AWS emulator started by `ackdev run --emulator`,
+	// for example localstack or moto.
+	Emulator *EmulatorConfig `yaml:"emulator,omitempty" json:"emulator,omitempty"`
+}
+
+// RunSettings are the settings of a controller run.
+type RunSettings struct {
+	// Flags is the map of flags/values passed to the controller binaries. For example
+	// to pass --aws-region=us-west-1 you'll need to set Flags to {"aws-region","us-west-1"}
+	Flags map[string]string `yaml:"flags,omitempty" json:"flags,omitempty"`
+	// Env contains environment variables passed to the controller processes.
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	// Kubeconfig is the path of the kubeconfig used by the controllers. If
+	// it's not specified the controllers run against the local kind cluster.
+	Kubeconfig string `yaml:"kubeconfig,omitempty" json:"kubeconfig,omitempty"`
+	// LogLevel sets the --log-level flag of the controllers.
+	LogLevel string `yaml:"logLevel,omitempty" json:"logLevel,omitempty"`
+}
+
+// RunProfile is a named run configuration.
+type RunProfile struct {
+	RunSettings
+	// Inherits is the name of the profile this profile is based on. Its
+	// settings are overridden by the ones of this profile.
+	Inherits string `yaml:"inherits,omitempty" json:"inherits,omitempty"`
+	// Services contains settings overriding the profile settings for some
+	// controllers, indexed by service name.
+	Services map[string]RunSettings `yaml:"services,omitempty" json:"services,omitempty"`
+}
+
+// Profile is a run profile resolved for a service controller, after applying
+// its inherited profiles and its service overrides.
+type Profile struct {
+	Name string
+	// Flags are the controller flags, including the log level.
+	Flags      map[string]string
+	Env        map[string]string
+	Kubeconfig string
+}
+
+// EnvList returns the profile environment variables in the form "key=value",
+// sorted by key.
+func (p *Profile) EnvList() []string {
+	env := make([]string, 0, len(p.Env))
+	for k, v := range p.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// Profile resolves a run profile for a service controller. An empty profile
+// name selects the default profile, which doesn't need to be configured.
+func (rc *RunConfig) Profile(name, service string) (*Profile, error) {
+	if name == "" {
+		name = DefaultRunProfile
+	}
+	chain, err := rc.profileChain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Profile{Name: name, Flags: map[string]string{}, Env: map[string]string{}}
+	// settings are applied from the base profile to the selected one, and
+	// each profile service overrides are applied after its own settings.
+	for i := len(chain) - 1; i >= 0; i-- {
+		p.apply(chain[i].RunSettings)
+		if override, ok := chain[i].Services[service]; ok {
+			p.apply(override)
+		}
+	}
+	return p, nil
+}
+
+func (p *Profile) apply(s RunSettings) {
+	for k, v := range s.Flags {
+		p.Flags[k] = v
+	}
+	for k, v := range s.Env {
+		p.Env[k] = v
+	}
+	if s.Kubeconfig != "" {
+		p.Kubeconfig = s.Kubeconfig
+	}
+	if s.LogLevel != "" {
+		p.Flags[logLevelFlag] = s.LogLevel
+	}
+}
+
+// profileChain returns a profile followed by the profiles it inherits from.
+func (rc *RunConfig) profileChain(name string) ([]RunProfile, error) {
+	chain := []RunProfile{}
+	visited := []string{}
+	for name != "" {
+		for _, v := range visited {
+			if v == name {
+				return nil, fmt.Errorf("invalid run profile %s: inheritance cycle %s", visited[0], strings.Join(append(visited, name), " -> "))
+			}
+		}
+		visited = append(visited, name)
+
+		profile, ok := rc.Profiles[name]
+		if !ok {
+			// the default profile is implicitly empty
+			if name == DefaultRunProfile {
+				break
+			}
+			return nil, fmt.Errorf("unknown run profile %s", name)
+		}
+		chain = append(chain, profile)
+		name = profile.Inherits
+	}
+	return chain, nil
+}
+
+// ProfileNames returns the names of the configured run profiles, sorted.
+func (rc *RunConfig) ProfileNames() []string {
+	names := make([]string, 0, len(rc.Profiles))
+	for name := range rc.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// migrateRunFlags moves the deprecated flat flags to the default profile.
+// Flags already set in the default profile take precedence.
+func migrateRunFlags(rc *RunConfig) {
+	if len(rc.Flags) == 0 {
+		rc.Flags = nil
+		return
+	}
+	if rc.Profiles == nil {
+		rc.Profiles = map[string]RunProfile{}
+	}
+	profile := rc.Profiles[DefaultRunProfile]
+	flags := map[string]string{}
+	for k, v := range rc.Flags {
+		flags[k] = v
+	}
+	for k, v := range profile.Flags {
+		flags[k] = v
+	}
+	profile.Flags = flags
+	rc.Profiles[DefaultRunProfile] = profile
+	rc.Flags = nil
+}
+
+// validateRunConfig checks that the run profiles can be resolved and that the
+// emulator configuration is valid.
+func validateRunConfig(rc *RunConfig) error {
+	for _, name := range rc.ProfileNames() {
+		_, err := rc.profileChain(name)
+		if err != nil {
+			return err
+		}
+	}
+	if emulator := rc.Emulator; emulator != nil {
+		if (emulator.Image == "") == (len(emulator.Command) == 0) {
+			return fmt.Errorf("invalid emulator configuration: exactly one of image or command must be set")
+		}
+		if emulator.Port <= 0 || emulator.Port > 65535 {
+			return fmt.Errorf("invalid emulator configuration: invalid port %d", emulator.Port)
+		}
+	}
+	return nil
+}
+
+// EmulatorConfig describes a local AWS emulator, either a container image or a
+// command starting a local process.
+type EmulatorConfig struct {
+	// Image is the emulator container image, run with docker.
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// Command is the emulator command, if it runs as a local process.
+	Command []string `yaml:"command,omitempty" json:"command,omitempty"`
+	// Port is the port the emulator listens to on localhost.
+	Port int `yaml:"port" json:"port"`
+	// HealthPath is the HTTP path polled to check that the emulator is
+	// ready. The default path is '/'.
+	HealthPath string `yaml:"healthPath,omitempty" json:"healthPath,omitempty"`
+	// Env contains the environment variables of the emulator.
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+}
@@ -0,0 +1,193 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfig_Profile(t *testing.T) {
+	rc := &RunConfig{
+		Profiles: map[string]RunProfile{
+			"default": {
+				RunSettings: RunSettings{
+					Flags: map[string]string{"aws-region": "eu-west-2", "aws-account-id": "111111111111"},
+					Env:   map[string]string{"AWS_PROFILE": "ack"},
+				},
+				Services: map[string]RunSettings{
+					"s3": {Flags: map[string]string{"aws-region": "us-east-1"}},
+				},
+			},
+			"us-west-2-debug": {
+				Inherits: "default",
+				RunSettings: RunSettings{
+					Flags:    map[string]string{"aws-region": "us-west-2"},
+					LogLevel: "debug",
+				},
+			},
+			"staging": {
+				Inherits: "us-west-2-debug",
+				RunSettings: RunSettings{
+					Kubeconfig: "/home/ack/.kube/staging",
+					Env:        map[string]string{"AWS_PROFILE": "staging"},
+				},
+				Services: map[string]RunSettings{
+					"s3": {LogLevel: "info"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		service string
+		want    *Profile
+	}{
+		{
+			name:    "default profile",
+			profile: "",
+			service: "ecr",
+			want: &Profile{
+				Name:  "default",
+				Flags: map[string]string{"aws-region": "eu-west-2", "aws-account-id": "111111111111"},
+				Env:   map[string]string{"AWS_PROFILE": "ack"},
+			},
+		},
+		{
+			name:    "service override",
+			profile: "default",
+			service: "s3",
+			want: &Profile{
+				Name:  "default",
+				Flags: map[string]string{"aws-region": "us-east-1", "aws-account-id": "111111111111"},
+				Env:   map[string]string{"AWS_PROFILE": "ack"},
+			},
+		},
+		{
+			name:    "inherited profile overrides the base service overrides",
+			profile: "us-west-2-debug",
+			service: "s3",
+			want: &Profile{
+				Name:  "us-west-2-debug",
+				Flags: map[string]string{"aws-region": "us-west-2", "aws-account-id": "111111111111", "log-level": "debug"},
+				Env:   map[string]string{"AWS_PROFILE": "ack"},
+			},
+		},
+		{
+			name:    "two levels of inheritance",
+			profile: "staging",
+			service: "s3",
+			want: &Profile{
+				Name:       "staging",
+				Flags:      map[string]string{"aws-region": "us-west-2", "aws-account-id": "111111111111", "log-level": "info"},
+				Env:        map[string]string{"AWS_PROFILE": "staging"},
+				Kubeconfig: "/home/ack/.kube/staging",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rc.Profile(tt.profile, tt.service)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := rc.Profile("prod", "s3")
+	assert.EqualError(t, err, "unknown run profile prod")
+
+	// the default profile doesn't need to be configured
+	p, err := (&RunConfig{}).Profile("", "s3")
+	require.NoError(t, err)
+	assert.Equal(t, &Profile{Name: "default", Flags: map[string]string{}, Env: map[string]string{}}, p)
+}
+
+func TestProfile_EnvList(t *testing.T) {
+	p := &Profile{Env: map[string]string{"B": "2", "A": "1"}}
+	assert.Equal(t, []string{"A=1", "B=2"}, p.EnvList())
+}
+
+func TestLoad_RunProfiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantProfiles map[string]RunProfile
+		wantErr      string
+	}{
+		{
+			name: "flat flags are loaded as the default profile",
+			content: `
+run:
+  flags:
+    aws-region: eu-west-2
+    log-level: info
+  profiles:
+    default:
+      flags:
+        log-level: debug
+`,
+			wantProfiles: map[string]RunProfile{
+				"default": {RunSettings: RunSettings{Flags: map[string]string{"aws-region": "eu-west-2", "log-level": "debug"}}},
+			},
+		},
+		{
+			name:         "empty flat flags",
+			content:      "run:\n  flags: {}\n",
+			wantProfiles: nil,
+		},
+		{
+			name: "unknown inherited profile",
+			content: `
+run:
+  profiles:
+    debug:
+      inherits: base
+`,
+			wantErr: "unknown run profile base",
+		},
+		{
+			name: "inheritance cycle",
+			content: `
+run:
+  profiles:
+    a:
+      inherits: b
+    b:
+      inherits: a
+`,
+			wantErr: "invalid run profile a: inheritance cycle a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			defer os.RemoveAll(filepath.Dir(path))
+
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Nil(t, cfg.RunConfig.Flags)
+			assert.Equal(t, tt.wantProfiles, cfg.RunConfig.Profiles)
+		})
+	}
+}
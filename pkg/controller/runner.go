@@ -0,0 +1,169 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// MainPackage is the package of the controller binaries, relative to the
+	// controller repositories.
+	MainPackage = "./cmd/controller"
+	// DefaultDebugPort is the port the debugger listens to by default.
+	DefaultDebugPort = 2345
+)
+
+// DebugBuildArgs are the go build arguments disabling the optimizations and
+// inlining, so that controllers can be debugged.
+var DebugBuildArgs = []string{"-gcflags=all=-N -l"}
+
+// Args returns the command line arguments built from the controller flags,
+// sorted by flag name.
+func Args(flags map[string]string) []string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, "--"+name+"="+flags[name])
+	}
+	return args
+}
+
+// Option is a function modifying a Runner.
+type Option func(*Runner)
+
+// WithGoBinary sets the go binary used to build the controllers.
+func WithGoBinary(path string) Option {
+	return func(r *Runner) {
+		r.goBinary = path
+	}
+}
+
+// WithDlvBinary sets the delve binary used to debug the controllers.
+func WithDlvBinary(path string) Option {
+	return func(r *Runner) {
+		r.dlvBinary = path
+	}
+}
+
+// WithOutput streams the output of the builds and of the controllers to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(r *Runner) {
+		r.out = out
+	}
+}
+
+// WithEnv adds environment variables, in the form "key=value", to the
+// controller processes.
+func WithEnv(env ...string) Option {
+	return func(r *Runner) {
+		r.env = append(r.env, env...)
+	}
+}
+
+// Runner builds service controllers from their local checkouts and runs them
+// locally.
+type Runner struct {
+	goBinary  string
+	dlvBinary string
+	out       asyncexec.Output
+	env       []string
+}
+
+// NewRunner instantiates a new Runner.
+func NewRunner(opts ...Option) *Runner {
+	r := &Runner{
+		goBinary:  "go",
+		dlvBinary: "dlv",
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Build builds the controller binary of a repository at the given path.
+// buildArgs are passed to go build, before the main package.
+func (r *Runner) Build(ctx context.Context, repo *repository.Repository, binPath string, buildArgs ...string) error {
+	err := os.MkdirAll(filepath.Dir(binPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"build", "-o", binPath}, buildArgs...)
+	args = append(args, MainPackage)
+	return tools.New(r.goBinary).InDir(repo.FullPath).Run(ctx, r.out, args...)
+}
+
+// Start starts a controller binary with the given flags. The controller is
+// stopped when the context is done, and the returned command must be waited.
+func (r *Runner) Start(ctx context.Context, binPath string, flags map[string]string) (*asyncexec.Cmd, error) {
+	return r.start(ctx, exec.Command(binPath, Args(flags)...))
+}
+
+// StartDebug starts a controller binary under a headless delve server
+// listening on the given port. The controller starts running immediately and
+// debuggers can attach to it at any time, using AttachCommand.
+func (r *Runner) StartDebug(ctx context.Context, binPath string, flags map[string]string, port int) (*asyncexec.Cmd, error) {
+	args := []string{
+		"exec",
+		"--headless",
+		"--listen", fmt.Sprintf("localhost:%d", port),
+		"--api-version", "2",
+		"--accept-multiclient",
+		"--continue",
+		binPath,
+	}
+	if len(flags) > 0 {
+		args = append(args, "--")
+		args = append(args, Args(flags)...)
+	}
+	return r.start(ctx, exec.Command(r.dlvBinary, args...))
+}
+
+// AttachCommand returns the command attaching a delve client to a controller
+// started with StartDebug.
+func AttachCommand(port int) string {
+	return fmt.Sprintf("dlv connect localhost:%d", port)
+}
+
+func (r *Runner) start(ctx context.Context, cmd *exec.Cmd) (*asyncexec.Cmd, error) {
+	if len(r.env) > 0 {
+		cmd.Env = append(os.Environ(), r.env...)
+	}
+
+	opts := []asyncexec.Option{asyncexec.WithContext(ctx)}
+	if r.out != nil {
+		opts = append(opts, asyncexec.WithOutput(r.out))
+	}
+	acmd := asyncexec.New(cmd, opts...)
+	err := acmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	return acmd, nil
+}
@@ -0,0 +1,129 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package controller
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  []string
+	}{
+		{
+			name:  "no flags",
+			flags: nil,
+			want:  []string{},
+		},
+		{
+			name: "sorted flags",
+			flags: map[string]string{
+				"log-level":                  "debug",
+				"aws-region":                 "us-west-2",
+				"enable-development-logging": "true",
+			},
+			want: []string{
+				"--aws-region=us-west-2",
+				"--enable-development-logging=true",
+				"--log-level=debug",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Args(tt.flags))
+		})
+	}
+}
+
+// lineRecorder records the stdout lines of a command.
+type lineRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *lineRecorder) WriteStdout(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, string(line))
+	return nil
+}
+
+func (r *lineRecorder) WriteStderr(line []byte) error {
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-controller")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the fake go binary writes a fake controller printing its arguments
+	goBinary, err := testutil.NewFakeBinary(dir, "go", `printf '#!/bin/sh\necho "$KUBECONFIG $@"\n' > "$3"; chmod +x "$3"`)
+	require.NoError(t, err)
+
+	out := &lineRecorder{}
+	r := NewRunner(WithGoBinary(goBinary), WithOutput(out), WithEnv("KUBECONFIG=/tmp/kubeconfig"))
+	repo := &repository.Repository{Name: "s3-controller", FullPath: dir}
+	binPath := filepath.Join(dir, "bin", "s3-controller")
+
+	ctx := context.Background()
+	require.NoError(t, r.Build(ctx, repo, binPath, "-gcflags=all=-N -l"))
+	calls, err := testutil.FakeBinaryCalls(dir, "go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build -o " + binPath + " -gcflags=all=-N -l ./cmd/controller"}, calls)
+
+	cmd, err := r.Start(ctx, binPath, map[string]string{"aws-region": "us-west-2"})
+	require.NoError(t, err)
+	_, err = cmd.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/tmp/kubeconfig --aws-region=us-west-2"}, out.lines)
+}
+
+func TestRunner_StartDebug(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-controller")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dlvBinary, err := testutil.NewFakeBinary(dir, "dlv", "")
+	require.NoError(t, err)
+
+	r := NewRunner(WithDlvBinary(dlvBinary))
+	cmd, err := r.StartDebug(context.Background(), "/bin/s3-controller", map[string]string{"aws-region": "us-west-2"}, 2346)
+	require.NoError(t, err)
+	_, err = cmd.Wait()
+	require.NoError(t, err)
+
+	calls, err := testutil.FakeBinaryCalls(dir, "dlv")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"exec --headless --listen localhost:2346 --api-version 2 --accept-multiclient --continue /bin/s3-controller -- --aws-region=us-west-2",
+	}, calls)
+	assert.Equal(t, "dlv connect localhost:2346", AttachCommand(2346))
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package controller
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/gomod"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+const (
+	// RuntimeModule is the module path of the ACK runtime.
+	RuntimeModule = "github.com/aws-controllers-k8s/runtime"
+	// AWSSDKGoModule is the module path of the AWS SDK used by the controllers.
+	AWSSDKGoModule = "github.com/aws/aws-sdk-go"
+
+	// GenerateMetadataFileName is the name of the file written by the code
+	// generator in each API version directory.
+	GenerateMetadataFileName = "ack-generate-metadata.yaml"
+	apisDirectory            = "apis"
+)
+
+// generateMetadata is the content of an ack-generate-metadata.yaml file.
+type generateMetadata struct {
+	GenerateInfo struct {
+		Version string `json:"version"`
+	} `json:"ack_generate_info"`
+	AWSSDKGoVersion string `json:"aws_sdk_go_version"`
+}
+
+// Versions contains the versions of the ACK components and of the AWS SDK a
+// controller is built with. Unknown versions are empty.
+type Versions struct {
+	// Runtime is the required version of the ACK runtime.
+	Runtime string
+	// CodeGenerator is the version of the code generator which generated the
+	// controller APIs.
+	CodeGenerator string
+	// AWSSDKGo is the required version of aws-sdk-go.
+	AWSSDKGo string
+}
+
+// ReadVersions reads the versions of a controller from its go.mod file and
+// from the code generator metadata of its APIs.
+func ReadVersions(dir string) (*Versions, error) {
+	modFile, err := gomod.ReadFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	versions := &Versions{
+		Runtime:  modFile.Version(RuntimeModule),
+		AWSSDKGo: modFile.Version(AWSSDKGoModule),
+	}
+
+	// each API version has its own metadata file, keep the most recent
+	// code generator version.
+	paths, err := filepath.Glob(filepath.Join(dir, apisDirectory, "*", GenerateMetadataFileName))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		metadata, err := readGenerateMetadata(path)
+		if err != nil {
+			return nil, err
+		}
+		if semver.Compare(metadata.GenerateInfo.Version, versions.CodeGenerator) > 0 {
+			versions.CodeGenerator = metadata.GenerateInfo.Version
+		}
+		if versions.AWSSDKGo == "" {
+			versions.AWSSDKGo = metadata.AWSSDKGoVersion
+		}
+	}
+	return versions, nil
+}
+
+func readGenerateMetadata(path string) (*generateMetadata, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &generateMetadata{}
+	err = yaml.Unmarshal(b, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package controller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/gomod"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReadVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-versions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ReadVersions(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	writeFile(t, filepath.Join(dir, gomod.FileName), `module github.com/aws-controllers-k8s/s3-controller
+
+go 1.17
+
+require (
+	github.com/aws-controllers-k8s/runtime v0.15.2
+	github.com/aws/aws-sdk-go v1.42.0
+)
+`)
+	versions, err := ReadVersions(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &Versions{Runtime: "v0.15.2", AWSSDKGo: "v1.42.0"}, versions)
+
+	writeFile(t, filepath.Join(dir, "apis", "v1alpha1", GenerateMetadataFileName), `ack_generate_info:
+  build_date: "2021-12-01T10:00:00Z"
+  build_hash: 0a1b2c3d
+  go_version: go1.17
+  version: v0.15.1
+api_directory_checksum: 1234
+api_version: v1alpha1
+aws_sdk_go_version: v1.42.0
+`)
+	writeFile(t, filepath.Join(dir, "apis", "v1beta1", GenerateMetadataFileName), `ack_generate_info:
+  version: v0.16.0
+api_version: v1beta1
+`)
+	versions, err = ReadVersions(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &Versions{Runtime: "v0.15.2", CodeGenerator: "v0.16.0", AWSSDKGo: "v1.42.0"}, versions)
+}
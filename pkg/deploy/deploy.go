@@ -0,0 +1,249 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/cluster"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// DefaultNamespace is the namespace where controllers are installed.
+	DefaultNamespace = "ack-system"
+
+	crdDirectory           = "config/crd"
+	helmChartDirectory     = "helm"
+	kustomizationFileName  = "kustomization.yaml"
+	awsRegionFlag          = "aws-region"
+	awsEndpointURLFlag     = "aws-endpoint-url"
+	helmRegionValue        = "aws.region"
+	helmEndpointURLValue   = "aws.endpoint_url"
+	helmImageRepository    = "image.repository"
+	helmImageTag           = "image.tag"
+	releaseNotFoundMessage = "not found"
+)
+
+var (
+	ErrMissingChart error = errors.New("missing helm chart")
+	ErrMissingCRDs  error = errors.New("missing CRDs")
+)
+
+// Options describes how a controller is deployed.
+type Options struct {
+	// Namespace is the namespace of the Helm release. Defaults to
+	// DefaultNamespace.
+	Namespace string
+	// Image is the controller image, in the form repository:tag. If empty the
+	// image configured in the Helm chart is used.
+	Image string
+	// LoadImage loads Image from the local docker daemon into the kind
+	// cluster before installing the chart.
+	LoadImage bool
+	// Flags are the controller flags, from which the Helm values are derived.
+	Flags map[string]string
+}
+
+func (o *Options) namespace() string {
+	if o.Namespace == "" {
+		return DefaultNamespace
+	}
+	return o.Namespace
+}
+
+// Option is a function modifying a Deployer.
+type Option func(*Deployer)
+
+// WithHelmBinary sets the helm binary invoked by the Deployer.
+func WithHelmBinary(path string) Option {
+	return func(d *Deployer) {
+		d.helmBinary = path
+	}
+}
+
+// WithOutput streams the output of the kubectl and helm commands to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(d *Deployer) {
+		d.out = out
+	}
+}
+
+// Deployer installs service controllers, from their local checkouts, into the
+// local kind cluster.
+type Deployer struct {
+	cluster    *cluster.Manager
+	helmBinary string
+	out        asyncexec.Output
+}
+
+// NewDeployer instantiates a new Deployer installing controllers into the
+// cluster managed by the given cluster manager.
+func NewDeployer(c *cluster.Manager, opts ...Option) *Deployer {
+	d := &Deployer{
+		cluster:    c,
+		helmBinary: "helm",
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *Deployer) helm() *tools.Tool {
+	return tools.New(d.helmBinary, "KUBECONFIG="+d.cluster.KubeconfigPath())
+}
+
+// ReleaseName returns the name of the Helm release of a controller.
+func ReleaseName(repo *repository.Repository) string {
+	return "ack-" + repo.Name
+}
+
+// Deploy applies the CRDs of a controller and installs, or upgrades, its Helm
+// chart.
+func (d *Deployer) Deploy(ctx context.Context, repo *repository.Repository, opts Options) error {
+	crdArgs, err := crdManifestArgs(repo)
+	if err != nil {
+		return err
+	}
+	chartPath := filepath.Join(repo.FullPath, helmChartDirectory)
+	if _, err := os.Stat(chartPath); err != nil {
+		return fmt.Errorf("%w: %s", ErrMissingChart, chartPath)
+	}
+
+	err = d.cluster.EnsureRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.Image != "" && opts.LoadImage {
+		err = d.cluster.LoadImage(ctx, opts.Image)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = d.cluster.Kubectl().Run(ctx, d.out, append([]string{"apply"}, crdArgs...)...)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"upgrade", "--install", ReleaseName(repo), chartPath,
+		"--namespace", opts.namespace(),
+		"--create-namespace",
+		"--wait",
+	}
+	for _, value := range HelmValues(opts.Flags, opts.Image) {
+		args = append(args, "--set", value)
+	}
+	return d.helm().Run(ctx, d.out, args...)
+}
+
+// ApplyCRDs only applies the CRDs of a controller, for example to run the
+// controller locally against the cluster.
+func (d *Deployer) ApplyCRDs(ctx context.Context, repo *repository.Repository) error {
+	crdArgs, err := crdManifestArgs(repo)
+	if err != nil {
+		return err
+	}
+	err = d.cluster.EnsureRunning(ctx)
+	if err != nil {
+		return err
+	}
+	return d.cluster.Kubectl().Run(ctx, d.out, append([]string{"apply"}, crdArgs...)...)
+}
+
+// Undeploy uninstalls the Helm chart of a controller and deletes its CRDs.
+// Deleting the CRDs also deletes all the custom resources of the controller.
+func (d *Deployer) Undeploy(ctx context.Context, repo *repository.Repository, opts Options) error {
+	crdArgs, err := crdManifestArgs(repo)
+	if err != nil {
+		return err
+	}
+
+	err = d.cluster.EnsureRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = d.helm().Run(ctx, d.out, "uninstall", ReleaseName(repo), "--namespace", opts.namespace())
+	if err != nil && !strings.Contains(err.Error(), releaseNotFoundMessage) {
+		return err
+	}
+
+	args := append([]string{"delete", "--ignore-not-found"}, crdArgs...)
+	return d.cluster.Kubectl().Run(ctx, d.out, args...)
+}
+
+// crdManifestArgs returns the kubectl arguments selecting the CRD manifests of
+// a controller. Kustomizations are preferred when they exist.
+func crdManifestArgs(repo *repository.Repository) ([]string, error) {
+	crdPath := filepath.Join(repo.FullPath, crdDirectory)
+	if _, err := os.Stat(crdPath); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMissingCRDs, crdPath)
+	}
+	if _, err := os.Stat(filepath.Join(crdPath, kustomizationFileName)); err == nil {
+		return []string{"--kustomize", crdPath}, nil
+	}
+	return []string{"--recursive", "--filename", crdPath}, nil
+}
+
+// HelmValues returns the Helm values, in the form key=value, derived from the
+// controller flags and image. Values are sorted by key.
+func HelmValues(flags map[string]string, image string) []string {
+	values := map[string]string{}
+	if region := flags[awsRegionFlag]; region != "" {
+		values[helmRegionValue] = region
+	}
+	if endpoint := flags[awsEndpointURLFlag]; endpoint != "" {
+		values[helmEndpointURLValue] = endpoint
+	}
+	if image != "" {
+		repository, tag := splitImage(image)
+		values[helmImageRepository] = repository
+		if tag != "" {
+			values[helmImageTag] = tag
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key+"="+values[key])
+	}
+	return result
+}
+
+// splitImage splits an image reference into its repository and tag. Registry
+// ports are not mistaken for tags.
+func splitImage(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i+1:], "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
@@ -0,0 +1,171 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/cluster"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestHelmValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		image string
+		want  []string
+	}{
+		{
+			name:  "no values",
+			flags: nil,
+			want:  []string{},
+		},
+		{
+			name: "region and endpoint",
+			flags: map[string]string{
+				"aws-region":       "us-west-2",
+				"aws-endpoint-url": "http://localhost:4566",
+				"log-level":        "debug",
+			},
+			want: []string{"aws.endpoint_url=http://localhost:4566", "aws.region=us-west-2"},
+		},
+		{
+			name:  "image with tag",
+			image: "ack-s3-controller:dev-1a2b3c4",
+			want:  []string{"image.repository=ack-s3-controller", "image.tag=dev-1a2b3c4"},
+		},
+		{
+			name:  "image with registry port and no tag",
+			image: "localhost:5000/ack-s3-controller",
+			want:  []string{"image.repository=localhost:5000/ack-s3-controller"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HelmValues(tt.flags, tt.image))
+		})
+	}
+}
+
+// newTestDeployer returns a deployer using fake binaries, and a controller
+// repository containing CRDs and a Helm chart.
+func newTestDeployer(t *testing.T, helmScript string) (*Deployer, *repository.Repository, string, func()) {
+	dir, err := ioutil.TempDir("", "ackdev-deploy")
+	require.NoError(t, err)
+
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, os.ModePerm))
+	kind, err := testutil.NewFakeBinary(binDir, "kind", `if [ "$1" = "get" ]; then echo ack; fi`)
+	require.NoError(t, err)
+	kubectl, err := testutil.NewFakeBinary(binDir, "kubectl", "")
+	require.NoError(t, err)
+	helm, err := testutil.NewFakeBinary(binDir, "helm", helmScript)
+	require.NoError(t, err)
+
+	repoPath := filepath.Join(dir, "s3-controller")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "config", "crd"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, "config", "crd", "kustomization.yaml"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "helm"), os.ModePerm))
+	repo := &repository.Repository{Name: "s3-controller", FullPath: repoPath}
+
+	clusterManager := cluster.NewManager(
+		config.ClusterConfig{Name: "ack"},
+		filepath.Join(dir, "state"),
+		cluster.WithKindBinary(kind),
+		cluster.WithKubectlBinary(kubectl),
+	)
+	return NewDeployer(clusterManager, WithHelmBinary(helm)), repo, binDir, func() { os.RemoveAll(dir) }
+}
+
+func TestDeployer_Deploy(t *testing.T) {
+	d, repo, binDir, cleanup := newTestDeployer(t, "")
+	defer cleanup()
+
+	err := d.Deploy(context.Background(), repo, Options{
+		Image:     "ack-s3-controller:dev",
+		LoadImage: true,
+		Flags:     map[string]string{"aws-region": "eu-west-1"},
+	})
+	require.NoError(t, err)
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "kind")
+	require.NoError(t, err)
+	assert.Equal(t, "load docker-image ack-s3-controller:dev --name ack", calls[len(calls)-1])
+
+	calls, err = testutil.FakeBinaryCalls(binDir, "kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apply --kustomize " + filepath.Join(repo.FullPath, "config", "crd")}, calls)
+
+	calls, err = testutil.FakeBinaryCalls(binDir, "helm")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"upgrade --install ack-s3-controller " + filepath.Join(repo.FullPath, "helm") +
+			" --namespace ack-system --create-namespace --wait" +
+			" --set aws.region=eu-west-1 --set image.repository=ack-s3-controller --set image.tag=dev",
+	}, calls)
+}
+
+func TestDeployer_Deploy_missingChart(t *testing.T) {
+	d, repo, _, cleanup := newTestDeployer(t, "")
+	defer cleanup()
+
+	require.NoError(t, os.RemoveAll(filepath.Join(repo.FullPath, "helm")))
+	err := d.Deploy(context.Background(), repo, Options{})
+	assert.True(t, errors.Is(err, ErrMissingChart))
+}
+
+func TestDeployer_Undeploy(t *testing.T) {
+	d, repo, binDir, cleanup := newTestDeployer(t, `echo "Error: uninstall: Release not loaded: ack-s3-controller: release: not found" >&2; exit 1`)
+	defer cleanup()
+
+	// missing releases are ignored
+	err := d.Undeploy(context.Background(), repo, Options{Namespace: "ack"})
+	require.NoError(t, err)
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "helm")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"uninstall ack-s3-controller --namespace ack"}, calls)
+
+	calls, err = testutil.FakeBinaryCalls(binDir, "kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delete --ignore-not-found --kustomize " + filepath.Join(repo.FullPath, "config", "crd")}, calls)
+}
+
+func TestDeployer_ApplyCRDs(t *testing.T) {
+	d, repo, binDir, cleanup := newTestDeployer(t, "")
+	defer cleanup()
+
+	// CRDs without kustomization are applied recursively
+	require.NoError(t, os.Remove(filepath.Join(repo.FullPath, "config", "crd", "kustomization.yaml")))
+	require.NoError(t, d.ApplyCRDs(context.Background(), repo))
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apply --recursive --filename " + filepath.Join(repo.FullPath, "config", "crd")}, calls)
+	_, err = testutil.FakeBinaryCalls(binDir, "helm")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+// Lookup returns the dependency with the given binary name, or nil if it
+// isn't one of the given dependencies.
+func Lookup(dependencies []Dependency, name string) *Dependency {
+	for i := range dependencies {
+		if dependencies[i].BinaryName == name {
+			return &dependencies[i]
+		}
+	}
+	return nil
+}
+
+// SatisfiesMinVersion returns true if version is greater or equal to the
+// dependency minimum version. Versions that can't be parsed are assumed to be
+// recent enough.
+func (t *Dependency) SatisfiesMinVersion(version string) bool {
+	if t.MinVersion == "" {
+		return true
+	}
+	v, err := semver.Parse(version)
+	if err != nil {
+		return true
+	}
+	return !v.LessThan(semver.MustParse(t.MinVersion))
+}
+
+// Check returns a *MissingError if the dependency isn't installed or is older
+// than its minimum version. The version command is run with DefaultTimeout.
+func (t *Dependency) Check(ctx context.Context) error {
+	_, err := t.BinPath()
+	if err != nil {
+		return &MissingError{Dependencies: []*Dependency{t}}
+	}
+	if t.MinVersion == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	version, err := t.Version(ctx)
+	if err == ErrorVersionNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !t.SatisfiesMinVersion(version) {
+		return &MissingError{Dependencies: []*Dependency{t}}
+	}
+	return nil
+}
+
+// Check checks the dependencies with the given binary names, looked up in
+// dependencies, and returns a *MissingError listing all the missing or
+// outdated ones.
+func Check(ctx context.Context, dependencies []Dependency, names ...string) error {
+	missing := &MissingError{}
+	for _, name := range names {
+		dependency := Lookup(dependencies, name)
+		if dependency == nil {
+			return fmt.Errorf("unknown dependency %q", name)
+		}
+		err := dependency.Check(ctx)
+		var missingErr *MissingError
+		if errors.As(err, &missingErr) {
+			missing.Dependencies = append(missing.Dependencies, missingErr.Dependencies...)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot check %s: %w", name, err)
+		}
+	}
+	if len(missing.Dependencies) > 0 {
+		return missing
+	}
+	return nil
+}
+
+// MissingError is returned when dependencies are not installed or are older
+// than their minimum version.
+type MissingError struct {
+	Dependencies []*Dependency
+}
+
+// Error implements the error interface.
+func (e *MissingError) Error() string {
+	names := make([]string, 0, len(e.Dependencies))
+	for _, dependency := range e.Dependencies {
+		names = append(names, dependency.String())
+	}
+	return "missing " + strings.Join(names, ", ")
+}
+
+// Merge returns the dependencies, where the extra dependencies replace the
+// ones with the same binary name and the other ones are appended in order.
+func Merge(dependencies []Dependency, extra []Dependency) []Dependency {
+	merged := append([]Dependency{}, dependencies...)
+	for _, dependency := range extra {
+		replaced := false
+		for i := range merged {
+			if merged[i].BinaryName == dependency.BinaryName {
+				merged[i] = dependency
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, dependency)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package deps
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+// withFakeTools replaces PATH with a directory containing the given fake
+// binaries.
+func withFakeTools(t *testing.T, binaries map[string]string) func() {
+	dir, err := ioutil.TempDir("", "ackdev-deps")
+	require.NoError(t, err)
+	for name, body := range binaries {
+		_, err := testutil.NewFakeBinary(dir, name, body)
+		require.NoError(t, err)
+	}
+
+	oldPath := os.Getenv("PATH")
+	os.Setenv("PATH", dir)
+	return func() {
+		os.Setenv("PATH", oldPath)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tools := []Dependency{
+		{BinaryName: "kind", GetVersionArgs: []string{"--version"}, MinVersion: "0.11.0"},
+		{BinaryName: "kubectl", GetVersionArgs: []string{"version"}, MinVersion: "1.19.0"},
+		{BinaryName: "helm", GetVersionArgs: []string{"version"}, MinVersion: "3.0.0"},
+		{BinaryName: "dlv", GetVersionArgs: []string{"version"}, Requirement: Optional},
+	}
+	cleanup := withFakeTools(t,
+		map[string]string{
+			"kind":    "echo 'kind version 0.9.0'",
+			"kubectl": "echo 'Client Version: v1.20.0'",
+			"helm":    "echo 'unknown version'",
+		},
+	)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		deps    []string
+		wantErr string
+	}{
+		{
+			name: "installed and recent enough",
+			deps: []string{"kubectl"},
+		},
+		{
+			name: "unknown version is accepted",
+			deps: []string{"helm"},
+		},
+		{
+			name:    "outdated and missing",
+			deps:    []string{"kind", "kubectl", "dlv"},
+			wantErr: "missing kind >= 0.11.0, dlv",
+		},
+		{
+			name:    "unknown dependency",
+			deps:    []string{"yq"},
+			wantErr: `unknown dependency "yq"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(context.Background(), tools, tt.deps...)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+
+	var missingErr *MissingError
+	assert.True(t, errors.As(Check(context.Background(), tools, "dlv"), &missingErr))
+
+	// dependencies are looked up in the given list only
+	assert.Nil(t, Lookup(tools, "go"))
+	assert.Equal(t, "kind", Lookup(tools, "kind").BinaryName)
+}
+
+func TestDependency_SatisfiesMinVersion(t *testing.T) {
+	kind := &Dependency{BinaryName: "kind", MinVersion: "0.11.0"}
+	assert.True(t, kind.SatisfiesMinVersion("v0.11.1"))
+	assert.True(t, kind.SatisfiesMinVersion("0.11.0"))
+	assert.False(t, kind.SatisfiesMinVersion("0.9.0"))
+	assert.True(t, kind.SatisfiesMinVersion("not a version"))
+	assert.True(t, (&Dependency{BinaryName: "go"}).SatisfiesMinVersion("1.14"))
+	assert.Equal(t, "kind >= 0.11.0", kind.String())
+}
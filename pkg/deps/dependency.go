@@ -14,15 +14,38 @@
 package deps
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os/exec"
 	"regexp"
+	"strings"
 )
 
 var (
 	ErrorVersionNotFound = errors.New("version not found in output")
 )
 
+// RequirementLevel tells whether a dependency is needed by the main ackdev
+// workflows or only by some features.
+type RequirementLevel int
+
+const (
+	// Required dependencies are needed by the main ackdev workflows.
+	Required RequirementLevel = iota
+	// Optional dependencies are only needed by some features, for example
+	// dlv is only needed to debug controllers.
+	Optional
+)
+
+// String returns the name of the requirement level.
+func (l RequirementLevel) String() string {
+	if l == Optional {
+		return "optional"
+	}
+	return "required"
+}
+
 var (
 	// DevelopmentTools is the list of ACK development tools
 	DevelopmentTools = []Dependency{
@@ -30,9 +53,14 @@ var (
 			BinaryName:     "go",
 			GetVersionArgs: []string{"version"},
 		},
+		{
+			BinaryName:     "docker",
+			GetVersionArgs: []string{"version", "--format", "{{.Client.Version}}"},
+		},
 		{
 			BinaryName:     "kind",
 			GetVersionArgs: []string{"--version"},
+			MinVersion:     "0.11.0",
 		},
 		{
 			BinaryName:     "helm",
@@ -41,6 +69,7 @@ var (
 		{
 			BinaryName:     "mockery",
 			GetVersionArgs: []string{"--version", "--quiet"},
+			Requirement:    Optional,
 		},
 		{
 			BinaryName:     "kubectl",
@@ -54,6 +83,16 @@ var (
 			BinaryName:     "controller-gen",
 			GetVersionArgs: []string{"--version"},
 		},
+		{
+			BinaryName:     "dlv",
+			GetVersionArgs: []string{"version"},
+			Requirement:    Optional,
+		},
+		{
+			BinaryName:     "python3",
+			GetVersionArgs: []string{"--version"},
+			Requirement:    Optional,
+		},
 	}
 )
 
@@ -64,6 +103,23 @@ type Dependency struct {
 	BinaryName string
 	// Arguments passed to the binary in order to get it version
 	GetVersionArgs []string
+	// VersionRegex extracts the version from the output of the version
+	// command, using its first capture group if it has one. If it's empty
+	// the first semantic version of the output is used.
+	VersionRegex string
+	// Requirement is the requirement level of the dependency.
+	Requirement RequirementLevel
+	// MinVersion is the oldest supported version of the dependency, if any.
+	MinVersion string
+}
+
+// String returns the binary name of the dependency, followed by its minimum
+// version if it has one.
+func (t *Dependency) String() string {
+	if t.MinVersion == "" {
+		return t.BinaryName
+	}
+	return fmt.Sprintf("%s >= %s", t.BinaryName, t.MinVersion)
 }
 
 // BinPath returns the path of a binary if it exists
@@ -75,15 +131,50 @@ func (t *Dependency) BinPath() (string, error) {
 	return path, nil
 }
 
-// Version returns the version of the binary.
-func (t *Dependency) Version() (string, error) {
-	cmd := exec.Command(t.BinaryName, t.GetVersionArgs...)
-	b, err := cmd.CombinedOutput()
+// Version returns the version of the binary. The version command is killed
+// and ctx.Err() returned when the context is done.
+func (t *Dependency) Version(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, t.BinaryName, t.GetVersionArgs...)
+	// the output is not waited for once the context is done, as processes
+	// spawned by the version command may keep it open.
+	type output struct {
+		b   []byte
+		err error
+	}
+	done := make(chan output, 1)
+	go func() {
+		b, err := cmd.CombinedOutput()
+		done <- output{b, err}
+	}()
+	var b []byte
+	var err error
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case out := <-done:
+		b, err = out.b, out.err
+	}
 	if err != nil {
+		// the command may return before ctx.Done() is selected once it
+		// is killed.
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if output := strings.TrimSpace(string(b)); output != "" {
+			return "", fmt.Errorf("%w: %s", err, strings.SplitN(output, "\n", 2)[0])
+		}
 		return "", err
 	}
 
-	version := getVersionFromString(string(b))
+	version := ""
+	if t.VersionRegex != "" {
+		version, err = extractVersion(t.VersionRegex, string(b))
+		if err != nil {
+			return "", err
+		}
+	} else {
+		version = getVersionFromString(string(b))
+	}
 	if len(version) == 0 {
 		return "", ErrorVersionNotFound
 	}
@@ -106,3 +197,20 @@ func getVersionFromString(s string) string {
 	}
 	return matches[0]
 }
+
+// extractVersion returns the first capture group of the first match of a
+// regular expression, or the whole match if the expression has no group.
+func extractVersion(regexExpr, s string) (string, error) {
+	re, err := regexp.Compile(regexExpr)
+	if err != nil {
+		return "", err
+	}
+	matches := re.FindStringSubmatch(s)
+	if len(matches) == 0 {
+		return "", nil
+	}
+	if len(matches) > 1 {
+		return matches[1], nil
+	}
+	return matches[0], nil
+}
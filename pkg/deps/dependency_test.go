@@ -56,3 +56,55 @@ func Test_getVersionFromString(t *testing.T) {
 		})
 	}
 }
+
+func Test_extractVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		regexExpr string
+		s         string
+		want      string
+	}{
+		{
+			name:      "capture group",
+			regexExpr: `aws-cli/([0-9.]+)`,
+			s:         "aws-cli/2.2.5 Python/3.8.8 Linux/5.10.0 exe/x86_64",
+			want:      "2.2.5",
+		},
+		{
+			name:      "whole match",
+			regexExpr: `v[0-9]+\.[0-9]+\.[0-9]+`,
+			s:         "golangci-lint has version v1.41.1 built from 2021-06-19",
+			want:      "v1.41.1",
+		},
+		{
+			name:      "no match",
+			regexExpr: `version ([0-9.]+)`,
+			s:         "unknown",
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := extractVersion(tt.regexExpr, tt.s)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	builtin := []Dependency{
+		{BinaryName: "go", GetVersionArgs: []string{"version"}},
+		{BinaryName: "kind", GetVersionArgs: []string{"--version"}},
+	}
+	extra := []Dependency{
+		{BinaryName: "yq", GetVersionArgs: []string{"--version"}},
+		{BinaryName: "kind", GetVersionArgs: []string{"version"}, MinVersion: "0.11.0"},
+	}
+	require.Equal(t, []Dependency{
+		{BinaryName: "go", GetVersionArgs: []string{"version"}},
+		{BinaryName: "kind", GetVersionArgs: []string{"version"}, MinVersion: "0.11.0"},
+		{BinaryName: "yq", GetVersionArgs: []string{"--version"}},
+	}, Merge(builtin, extra))
+	require.Len(t, builtin, 2)
+}
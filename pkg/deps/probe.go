@@ -0,0 +1,101 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default timeout of the dependency version
+	// commands.
+	DefaultTimeout = 5 * time.Second
+
+	StatusOK               = "OK"
+	StatusNotFound         = "NOT FOUND"
+	StatusNotFoundOptional = "NOT FOUND (OPTIONAL)"
+	StatusOutdated         = "OUTDATED"
+	StatusError            = "ERROR"
+	StatusTimeout          = "TIMEOUT"
+)
+
+// ProbeResult is the state of a dependency on the local machine.
+type ProbeResult struct {
+	Dependency Dependency
+	// Status is one of the Status constants. Outdated dependencies have the
+	// status "OUTDATED (>= <minimum version>)".
+	Status string
+	// Path is the path of the dependency binary, if it was found.
+	Path string
+	// Version is the version of the dependency, or an empty string if it
+	// couldn't be found in the output of the version command.
+	Version string
+	// Err is the error of the version command, for the ERROR and TIMEOUT
+	// statuses.
+	Err error
+}
+
+// Probe looks up the dependencies binaries and runs their version commands
+// concurrently, each with the given timeout. The results are returned in the
+// order of the dependencies.
+func Probe(ctx context.Context, dependencies []Dependency, timeout time.Duration) []*ProbeResult {
+	results := make([]*ProbeResult, len(dependencies))
+	var wg sync.WaitGroup
+	for i := range dependencies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = probe(ctx, dependencies[i], timeout)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// probe returns the state of a single dependency.
+func probe(ctx context.Context, dependency Dependency, timeout time.Duration) *ProbeResult {
+	result := &ProbeResult{Dependency: dependency}
+	path, err := dependency.BinPath()
+	if err != nil {
+		result.Status = StatusNotFound
+		if dependency.Requirement == Optional {
+			result.Status = StatusNotFoundOptional
+		}
+		return result
+	}
+	result.Path = path
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	version, err := dependency.Version(ctx)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result.Status = StatusTimeout
+		result.Err = fmt.Errorf("version command timed out after %v: %w", timeout, err)
+	case err != nil && err != ErrorVersionNotFound:
+		result.Status = StatusError
+		result.Err = err
+	case !dependency.SatisfiesMinVersion(version):
+		result.Status = StatusOutdated + " (>= " + dependency.MinVersion + ")"
+		result.Version = version
+	default:
+		result.Status = StatusOK
+		result.Version = version
+	}
+	return result
+}
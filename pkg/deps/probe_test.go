@@ -0,0 +1,72 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package deps
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	tools := []Dependency{
+		{BinaryName: "go", GetVersionArgs: []string{"version"}},
+		{BinaryName: "kubectl", GetVersionArgs: []string{"version"}},
+		{BinaryName: "kind", GetVersionArgs: []string{"--version"}, MinVersion: "0.11.0"},
+		{BinaryName: "helm", GetVersionArgs: []string{"version"}},
+		{BinaryName: "kustomize", GetVersionArgs: []string{"version"}},
+		{BinaryName: "controller-gen", GetVersionArgs: []string{"--version"}},
+		{BinaryName: "dlv", GetVersionArgs: []string{"version"}, Requirement: Optional},
+		{BinaryName: "yq", GetVersionArgs: []string{"--version"}},
+	}
+	cleanup := withFakeTools(t,
+		map[string]string{
+			"go":        "echo 'go version go1.15.6 linux/amd64'",
+			"kubectl":   "exec /bin/sleep 10",
+			"kind":      "echo 'kind version 0.9.0'",
+			"helm":      "echo 'Error: unknown flag' >&2; exit 1",
+			"kustomize": "exec /bin/sleep 10",
+			"yq":        "echo 'unknown'",
+		},
+	)
+	defer cleanup()
+
+	start := time.Now()
+	results := Probe(context.Background(), tools, 500*time.Millisecond)
+	// the version commands that time out run concurrently
+	assert.True(t, time.Since(start) < 900*time.Millisecond, "probing took %v", time.Since(start))
+
+	type row struct {
+		name, status, version string
+		hasErr                bool
+	}
+	rows := []row{}
+	for _, result := range results {
+		rows = append(rows, row{result.Dependency.BinaryName, result.Status, result.Version, result.Err != nil})
+	}
+	assert.Equal(t, []row{
+		{"go", StatusOK, "1.15.6", false},
+		{"kubectl", StatusTimeout, "", true},
+		{"kind", "OUTDATED (>= 0.11.0)", "0.9.0", false},
+		{"helm", StatusError, "", true},
+		{"kustomize", StatusTimeout, "", true},
+		{"controller-gen", StatusNotFound, "", false},
+		{"dlv", StatusNotFoundOptional, "", false},
+		{"yq", StatusOK, "", false},
+	}, rows)
+}
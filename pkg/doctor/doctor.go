@@ -0,0 +1,413 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package doctor diagnoses the development environment of ACK contributors:
+// Go toolchain, docker, disk space, git identity and Github token.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+
+	// ACKModulePrefix is the prefix of the ACK Go modules.
+	ACKModulePrefix = "github.com/aws-controllers-k8s"
+
+	defaultCommandTimeout = 10 * time.Second
+
+	// free disk space thresholds under the root directory
+	diskSpaceWarnThreshold = 10 << 30
+	diskSpaceFailThreshold = 2 << 30
+)
+
+var (
+	goVersionRegex = regexp.MustCompile(`go([0-9]+(\.[0-9]+)*)`)
+	// requiredTokenScopes are needed to fork and rename repositories, and
+	// recommendedTokenScopes to delete forks.
+	requiredTokenScopes    = []string{"repo"}
+	recommendedTokenScopes = []string{"delete_repo"}
+)
+
+// Result is the result of a check.
+type Result struct {
+	// Check is the name of the check.
+	Check  string
+	Status Status
+	// Message describes what was found.
+	Message string
+	// Hint tells how to fix a failed check or a warning.
+	Hint string
+}
+
+func pass(check, format string, a ...interface{}) *Result {
+	return &Result{Check: check, Status: StatusPass, Message: fmt.Sprintf(format, a...)}
+}
+
+func warn(check, hint, format string, a ...interface{}) *Result {
+	return &Result{Check: check, Status: StatusWarn, Message: fmt.Sprintf(format, a...), Hint: hint}
+}
+
+func fail(check, hint, format string, a ...interface{}) *Result {
+	return &Result{Check: check, Status: StatusFail, Message: fmt.Sprintf(format, a...), Hint: hint}
+}
+
+// Option is a functional option for Doctor.
+type Option func(*Doctor)
+
+// WithGoBinary sets the go binary invoked by the Doctor.
+func WithGoBinary(path string) Option {
+	return func(d *Doctor) {
+		d.goBinary = path
+	}
+}
+
+// WithGitBinary sets the git binary invoked by the Doctor.
+func WithGitBinary(path string) Option {
+	return func(d *Doctor) {
+		d.gitBinary = path
+	}
+}
+
+// WithDockerBinary sets the docker binary invoked by the Doctor.
+func WithDockerBinary(path string) Option {
+	return func(d *Doctor) {
+		d.dockerBinary = path
+	}
+}
+
+// WithPath sets the PATH searched for the Go binaries directory. The default
+// is the PATH of the ackdev process.
+func WithPath(path string) Option {
+	return func(d *Doctor) {
+		d.path = path
+	}
+}
+
+// Doctor runs the environment checks.
+type Doctor struct {
+	goBinary     string
+	gitBinary    string
+	dockerBinary string
+	path         string
+}
+
+// New returns a new Doctor.
+func New(opts ...Option) *Doctor {
+	d := &Doctor{
+		goBinary:     "go",
+		gitBinary:    "git",
+		dockerBinary: "docker",
+		path:         os.Getenv("PATH"),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// output runs a command and returns its trimmed standard output.
+func output(ctx context.Context, binary string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultCommandTimeout)
+	defer cancel()
+	b, err := exec.CommandContext(ctx, binary, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// goEnv returns the values of Go environment variables.
+func (d *Doctor) goEnv(ctx context.Context, names ...string) (map[string]string, error) {
+	out, err := output(ctx, d.goBinary, append([]string{"env"}, names...)...)
+	if err != nil {
+		return nil, err
+	}
+	// go env prints a line per variable, the trailing empty ones are trimmed
+	lines := strings.Split(out, "\n")
+	if len(lines) > len(names) {
+		return nil, fmt.Errorf("unexpected go env output %q", out)
+	}
+	env := map[string]string{}
+	for i, line := range lines {
+		env[names[i]] = strings.TrimSpace(line)
+	}
+	return env, nil
+}
+
+// CheckGoVersion checks that the installed Go version is at least the
+// version of the go directives of the controllers, given as a map of
+// repository names to go directives.
+func (d *Doctor) CheckGoVersion(ctx context.Context, goDirectives map[string]string) *Result {
+	const check = "Go version"
+	out, err := output(ctx, d.goBinary, "version")
+	if err != nil {
+		return fail(check, "install Go from https://golang.org/dl/", "cannot run go: %v", err)
+	}
+	matches := goVersionRegex.FindStringSubmatch(out)
+	if matches == nil {
+		return warn(check, "", "cannot find the version in %q", out)
+	}
+	goVersion, err := semver.Parse(matches[1])
+	if err != nil {
+		return warn(check, "", "cannot parse Go version %s", matches[1])
+	}
+
+	repos := make([]string, 0, len(goDirectives))
+	for repo := range goDirectives {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	outdated := []string{}
+	var required *semver.Version
+	for _, repo := range repos {
+		directive, err := semver.Parse(goDirectives[repo])
+		if err != nil {
+			continue
+		}
+		if goVersion.LessThan(directive) {
+			outdated = append(outdated, fmt.Sprintf("%s (go %s)", repo, goDirectives[repo]))
+		}
+		if required == nil || required.LessThan(directive) {
+			required = directive
+		}
+	}
+	if len(outdated) > 0 {
+		return fail(check,
+			fmt.Sprintf("install Go %d.%d or later from https://golang.org/dl/", required.Major, required.Minor),
+			"go %s is older than the go directive of %s", matches[1], strings.Join(outdated, ", "),
+		)
+	}
+	if required == nil {
+		return pass(check, "go %s, no controller go.mod found", matches[1])
+	}
+	return pass(check, "go %s, controllers require go %d.%d", matches[1], required.Major, required.Minor)
+}
+
+// CheckGoBinPath checks that the directory where `go install` puts binaries
+// is part of the PATH.
+func (d *Doctor) CheckGoBinPath(ctx context.Context) *Result {
+	const check = "Go binaries in PATH"
+	env, err := d.goEnv(ctx, "GOPATH", "GOBIN")
+	if err != nil {
+		return fail(check, "install Go from https://golang.org/dl/", "cannot run go env: %v", err)
+	}
+	binDir := env["GOBIN"]
+	if binDir == "" {
+		gopaths := filepath.SplitList(env["GOPATH"])
+		if len(gopaths) == 0 {
+			return warn(check, "set GOPATH or GOBIN", "GOPATH and GOBIN are not set")
+		}
+		binDir = filepath.Join(gopaths[0], "bin")
+	}
+	binDir = filepath.Clean(binDir)
+	for _, dir := range filepath.SplitList(d.path) {
+		if dir != "" && filepath.Clean(dir) == binDir {
+			return pass(check, "%s is in PATH", binDir)
+		}
+	}
+	return warn(check,
+		fmt.Sprintf("add it to your shell profile: export PATH=\"$PATH:%s\"", binDir),
+		"%s is not in PATH, tools installed with go install are not found", binDir,
+	)
+}
+
+// CheckGoProxy checks that the GOPROXY and GOPRIVATE settings allow to
+// download the ACK modules and their dependencies.
+func (d *Doctor) CheckGoProxy(ctx context.Context) *Result {
+	const check = "Go module proxy"
+	env, err := d.goEnv(ctx, "GOPROXY", "GOPRIVATE", "GONOPROXY")
+	if err != nil {
+		return fail(check, "install Go from https://golang.org/dl/", "cannot run go env: %v", err)
+	}
+	if env["GOPROXY"] == "off" {
+		return fail(check, "go env -w GOPROXY=https://proxy.golang.org,direct", "GOPROXY=off, modules cannot be downloaded")
+	}
+	for _, name := range []string{"GOPRIVATE", "GONOPROXY"} {
+		if matchesModule(env[name], ACKModulePrefix) {
+			return warn(check,
+				fmt.Sprintf("remove %s from %s unless you use private forks of the ACK modules", ACKModulePrefix, name),
+				"%s=%s, the ACK modules are downloaded without the module proxy", name, env[name],
+			)
+		}
+	}
+	return pass(check, "GOPROXY=%s", env["GOPROXY"])
+}
+
+// matchesModule returns true if one of the comma separated glob patterns
+// matches the module path or one of its prefixes, like GOPRIVATE does.
+func matchesModule(patterns, module string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		n := strings.Count(pattern, "/") + 1
+		parts := strings.SplitN(module, "/", n+1)
+		if len(parts) < n {
+			continue
+		}
+		prefix := strings.Join(parts[:n], "/")
+		if matched, _ := filepath.Match(pattern, prefix); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckDocker checks that the docker daemon, used by kind and to build
+// controller images, is reachable.
+func (d *Doctor) CheckDocker(ctx context.Context) *Result {
+	const check = "Docker daemon"
+	if _, err := exec.LookPath(d.dockerBinary); err != nil {
+		return fail(check, "install docker from https://docs.docker.com/get-docker/", "docker not found")
+	}
+	version, err := output(ctx, d.dockerBinary, "info", "--format", "{{.ServerVersion}}")
+	if err != nil {
+		return fail(check, "start the docker daemon, and check that your user can access it", "cannot reach the docker daemon: %v", err)
+	}
+	return pass(check, "docker server %s", version)
+}
+
+// CheckDiskSpace checks the free disk space under the directory. The closest
+// existing parent is used if the directory doesn't exist yet.
+func (d *Doctor) CheckDiskSpace(dir string) *Result {
+	const check = "Disk space"
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return warn(check, "", "cannot get the free disk space of %s: %v", dir, err)
+	}
+	hint := "free some disk space, for example with docker system prune"
+	switch {
+	case free < diskSpaceFailThreshold:
+		return fail(check, hint, "%s free under %s", formatBytes(free), dir)
+	case free < diskSpaceWarnThreshold:
+		return warn(check, hint, "%s free under %s", formatBytes(free), dir)
+	default:
+		return pass(check, "%s free under %s", formatBytes(free), dir)
+	}
+}
+
+// formatBytes formats a size in GiB.
+func formatBytes(b uint64) string {
+	return fmt.Sprintf("%.1f GiB", float64(b)/(1<<30))
+}
+
+// CheckConfig loads and validates the configuration file. The configuration
+// is nil if the check fails.
+func (d *Doctor) CheckConfig(path string) (*config.Config, *Result) {
+	const check = "Configuration"
+	cfg, err := config.Load(path)
+	if os.IsNotExist(err) {
+		return nil, fail(check, "run ackdev setup", "%s doesn't exist", path)
+	}
+	if err != nil {
+		return nil, fail(check, "fix it with ackdev edit config", "invalid configuration: %v", err)
+	}
+	return cfg, pass(check, "%s is valid", path)
+}
+
+// CheckGitIdentity checks that git commits have an author.
+func (d *Doctor) CheckGitIdentity(ctx context.Context) *Result {
+	const check = "Git identity"
+	if _, err := exec.LookPath(d.gitBinary); err != nil {
+		return fail(check, "install git from https://git-scm.com/downloads", "git not found")
+	}
+	missing := []string{}
+	values := []string{}
+	for _, key := range []string{"user.name", "user.email"} {
+		// git config exits with 1 when the key isn't set
+		value, _ := output(ctx, d.gitBinary, "config", "--get", key)
+		if value == "" {
+			missing = append(missing, key)
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(missing) > 0 {
+		hints := []string{}
+		for _, key := range missing {
+			hints = append(hints, fmt.Sprintf("git config --global %s <%s>", key, strings.TrimPrefix(key, "user.")))
+		}
+		return fail(check, strings.Join(hints, " && "), "%s not set, commits cannot be created", strings.Join(missing, " and "))
+	}
+	return pass(check, "%s <%s>", values[0], values[1])
+}
+
+// TokenScoper returns the scopes of a Github token.
+type TokenScoper interface {
+	TokenScopes(ctx context.Context) ([]string, error)
+}
+
+// CheckGithubToken checks that the Github token is valid and has the scopes
+// needed to manage forks. client is nil when no token is configured.
+func (d *Doctor) CheckGithubToken(ctx context.Context, client TokenScoper) *Result {
+	const check = "Github token"
+	hint := "create a token at https://github.com/settings/tokens and run ackdev edit config"
+	if client == nil {
+		return warn(check, hint, "no token configured, forks cannot be managed")
+	}
+	scopes, err := client.TokenScopes(ctx)
+	if err != nil {
+		return fail(check, hint, "cannot use the token: %v", err)
+	}
+	if len(scopes) == 0 {
+		return warn(check, "", "the token has no OAuth scopes, fine-grained tokens permissions cannot be checked")
+	}
+	if missing := missingScopes(scopes, requiredTokenScopes); len(missing) > 0 {
+		return fail(check, hint, "missing scopes %s", strings.Join(missing, ", "))
+	}
+	if missing := missingScopes(scopes, recommendedTokenScopes); len(missing) > 0 {
+		return warn(check, "add the delete_repo scope to delete forks with ackdev", "missing scopes %s", strings.Join(missing, ", "))
+	}
+	return pass(check, "scopes %s", strings.Join(scopes, ", "))
+}
+
+func missingScopes(scopes, wanted []string) []string {
+	missing := []string{}
+	for _, scope := range wanted {
+		if !util.InStrings(scope, scopes) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,290 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package doctor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+// fakeGo is a go binary printing its version and environment variables set
+// with FAKE_<NAME> variables.
+const fakeGo = `
+case "$1" in
+version) echo "go version go1.15.6 linux/amd64" ;;
+env) shift; for name in "$@"; do eval "echo \$FAKE_$name"; done ;;
+esac`
+
+func newDoctor(t *testing.T, binaries map[string]string, opts ...Option) (*Doctor, func()) {
+	dir, err := ioutil.TempDir("", "ackdev-doctor")
+	require.NoError(t, err)
+	for name, body := range binaries {
+		path, err := testutil.NewFakeBinary(dir, name, body)
+		require.NoError(t, err)
+		switch name {
+		case "go":
+			opts = append(opts, WithGoBinary(path))
+		case "git":
+			opts = append(opts, WithGitBinary(path))
+		case "docker":
+			opts = append(opts, WithDockerBinary(path))
+		}
+	}
+	return New(opts...), func() { os.RemoveAll(dir) }
+}
+
+func TestDoctor_CheckGoVersion(t *testing.T) {
+	d, cleanup := newDoctor(t, map[string]string{"go": fakeGo})
+	defer cleanup()
+
+	ctx := context.Background()
+	result := d.CheckGoVersion(ctx, map[string]string{"s3-controller": "1.14", "ecr-controller": "1.15"})
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "go 1.15.6, controllers require go 1.15", result.Message)
+
+	result = d.CheckGoVersion(ctx, map[string]string{"s3-controller": "1.17", "ecr-controller": "1.16", "sns-controller": "1.14"})
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "go 1.15.6 is older than the go directive of ecr-controller (go 1.16), s3-controller (go 1.17)", result.Message)
+	assert.Equal(t, "install Go 1.17 or later from https://golang.org/dl/", result.Hint)
+
+	result = d.CheckGoVersion(ctx, nil)
+	assert.Equal(t, StatusPass, result.Status)
+}
+
+func TestDoctor_CheckGoBinPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		path       string
+		wantStatus Status
+	}{
+		{
+			name:       "GOPATH bin in PATH",
+			env:        map[string]string{"FAKE_GOPATH": "/home/ack/go"},
+			path:       "/usr/bin:/home/ack/go/bin/",
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "GOBIN in PATH",
+			env:        map[string]string{"FAKE_GOPATH": "/home/ack/go", "FAKE_GOBIN": "/home/ack/bin"},
+			path:       "/usr/bin:/home/ack/bin",
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "GOBIN not in PATH",
+			env:        map[string]string{"FAKE_GOPATH": "/home/ack/go", "FAKE_GOBIN": "/home/ack/bin"},
+			path:       "/usr/bin:/home/ack/go/bin",
+			wantStatus: StatusWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+			d, cleanup := newDoctor(t, map[string]string{"go": fakeGo}, WithPath(tt.path))
+			defer cleanup()
+
+			result := d.CheckGoBinPath(context.Background())
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+		})
+	}
+}
+
+func TestDoctor_CheckGoProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantStatus Status
+	}{
+		{
+			name:       "default proxy",
+			env:        map[string]string{"FAKE_GOPROXY": "https://proxy.golang.org,direct"},
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "unrelated private modules",
+			env:        map[string]string{"FAKE_GOPROXY": "https://proxy.golang.org,direct", "FAKE_GOPRIVATE": "github.com/my-org/*"},
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "private ACK modules",
+			env:        map[string]string{"FAKE_GOPROXY": "https://proxy.golang.org,direct", "FAKE_GOPRIVATE": "github.com/my-org,github.com/aws-controllers-k8s"},
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "proxy disabled",
+			env:        map[string]string{"FAKE_GOPROXY": "off"},
+			wantStatus: StatusFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+			d, cleanup := newDoctor(t, map[string]string{"go": fakeGo})
+			defer cleanup()
+
+			result := d.CheckGoProxy(context.Background())
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+		})
+	}
+}
+
+func Test_matchesModule(t *testing.T) {
+	assert.True(t, matchesModule("github.com/aws-controllers-k8s", "github.com/aws-controllers-k8s"))
+	assert.True(t, matchesModule("*.corp.com, github.com/aws-*", "github.com/aws-controllers-k8s"))
+	assert.True(t, matchesModule("github.com", "github.com/aws-controllers-k8s"))
+	assert.False(t, matchesModule("github.com/aws", "github.com/aws-controllers-k8s"))
+	assert.False(t, matchesModule("", "github.com/aws-controllers-k8s"))
+}
+
+func TestDoctor_CheckDocker(t *testing.T) {
+	d, cleanup := newDoctor(t, map[string]string{"docker": "echo 20.10.7"})
+	defer cleanup()
+	result := d.CheckDocker(context.Background())
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "docker server 20.10.7", result.Message)
+
+	d, cleanup = newDoctor(t, map[string]string{"docker": "echo 'Cannot connect to the Docker daemon' >&2; exit 1"})
+	defer cleanup()
+	result = d.CheckDocker(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Contains(t, result.Message, "Cannot connect to the Docker daemon")
+
+	result = New(WithDockerBinary("/nonexistent/docker")).CheckDocker(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+}
+
+func TestDoctor_CheckDiskSpace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the closest existing parent of missing directories is checked
+	result := New().CheckDiskSpace(filepath.Join(dir, "src", "github.com"))
+	assert.NotEmpty(t, result.Status)
+	assert.Contains(t, result.Message, "free under "+dir)
+}
+
+func TestDoctor_CheckConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	d := New()
+	path := filepath.Join(dir, "ackdev.yaml")
+	cfg, result := d.CheckConfig(path)
+	assert.Nil(t, cfg)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "run ackdev setup", result.Hint)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("cluster:\n  workers: -1\n"), 0644))
+	cfg, result = d.CheckConfig(path)
+	assert.Nil(t, cfg)
+	assert.Equal(t, StatusFail, result.Status)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("rootDirectory: /src\n"), 0644))
+	cfg, result = d.CheckConfig(path)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "/src", cfg.RootDirectory)
+	assert.Equal(t, StatusPass, result.Status)
+}
+
+func TestDoctor_CheckGitIdentity(t *testing.T) {
+	d, cleanup := newDoctor(t, map[string]string{"git": `
+case "$3" in
+user.name) echo "ACK Bot" ;;
+user.email) echo "ack-bot@example.com" ;;
+esac`})
+	defer cleanup()
+	result := d.CheckGitIdentity(context.Background())
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "ACK Bot <ack-bot@example.com>", result.Message)
+
+	d, cleanup = newDoctor(t, map[string]string{"git": `[ "$3" = user.name ] && echo "ACK Bot" || exit 1`})
+	defer cleanup()
+	result = d.CheckGitIdentity(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "user.email not set, commits cannot be created", result.Message)
+	assert.Equal(t, "git config --global user.email <email>", result.Hint)
+}
+
+type fakeTokenScoper struct {
+	scopes []string
+	err    error
+}
+
+func (f *fakeTokenScoper) TokenScopes(context.Context) ([]string, error) {
+	return f.scopes, f.err
+}
+
+func TestDoctor_CheckGithubToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     TokenScoper
+		wantStatus Status
+	}{
+		{
+			name:       "no token",
+			client:     nil,
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "invalid token",
+			client:     &fakeTokenScoper{err: errors.New("401 Bad credentials")},
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "missing repo scope",
+			client:     &fakeTokenScoper{scopes: []string{"read:org"}},
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "missing delete_repo scope",
+			client:     &fakeTokenScoper{scopes: []string{"repo"}},
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "fine-grained token",
+			client:     &fakeTokenScoper{scopes: []string{}},
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "all scopes",
+			client:     &fakeTokenScoper{scopes: []string{"repo", "delete_repo"}},
+			wantStatus: StatusPass,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New().CheckGithubToken(context.Background(), tt.client)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+		})
+	}
+}
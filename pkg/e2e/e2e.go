@@ -0,0 +1,158 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// TestDirectory is the directory of the e2e tests, relative to the
+	// controller repositories.
+	TestDirectory = "test/e2e"
+
+	// JUnitFileName is the name of the JUnit report written in the artifacts
+	// directory.
+	JUnitFileName = "junit.xml"
+
+	testInfraSourceDirectory = "src"
+	awsRegionFlag            = "aws-region"
+	artifactsTimeFormat      = "20060102-150405"
+)
+
+var (
+	ErrMissingTests error = errors.New("missing e2e tests")
+)
+
+// ArtifactsDirectory returns a new timestamped directory where the logs and
+// results of an e2e run are collected:
+// <stateDirectory>/artifacts/e2e/<controller>/<timestamp>
+func ArtifactsDirectory(stateDirectory string, repo *repository.Repository, t time.Time) (string, error) {
+	dir := filepath.Join(stateDirectory, "artifacts", "e2e", repo.Name, t.Format(artifactsTimeFormat))
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Options describes how the e2e tests are run.
+type Options struct {
+	// Markers is a pytest marker expression selecting the tests to run.
+	Markers string
+	// Args are additional arguments passed to pytest.
+	Args []string
+	// Kubeconfig is the path of the cluster kubeconfig.
+	Kubeconfig string
+	// Flags are the controller flags. The AWS region is passed to the tests.
+	Flags map[string]string
+	// ArtifactsDirectory is the directory where the JUnit report is written.
+	ArtifactsDirectory string
+}
+
+// Option is a function modifying a Runner.
+type Option func(*Runner)
+
+// WithPythonBinary sets the python binary running pytest.
+func WithPythonBinary(path string) Option {
+	return func(r *Runner) {
+		r.pythonBinary = path
+	}
+}
+
+// WithOutput streams the pytest output to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(r *Runner) {
+		r.out = out
+	}
+}
+
+// Runner runs the e2e tests of a controller, using the acktest library of a
+// local test-infra checkout.
+type Runner struct {
+	testInfraPath string
+	pythonBinary  string
+	out           asyncexec.Output
+}
+
+// NewRunner instantiates a new Runner using the test-infra checkout found at
+// the given path.
+func NewRunner(testInfraPath string, opts ...Option) *Runner {
+	r := &Runner{
+		testInfraPath: testInfraPath,
+		pythonBinary:  "python3",
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run runs pytest in the e2e test directory of a controller.
+func (r *Runner) Run(ctx context.Context, repo *repository.Repository, opts Options) error {
+	testDir := filepath.Join(repo.FullPath, TestDirectory)
+	if _, err := os.Stat(testDir); err != nil {
+		return fmt.Errorf("%w: %s", ErrMissingTests, testDir)
+	}
+
+	python := tools.New(r.pythonBinary, r.Env(repo, opts)...).InDir(testDir)
+	return python.Run(ctx, r.out, r.Args(opts)...)
+}
+
+// Args returns the python arguments running pytest.
+func (r *Runner) Args(opts Options) []string {
+	args := []string{
+		"-m", "pytest",
+		"-o", "log_cli=true",
+		"--log-cli-level", "INFO",
+	}
+	if opts.ArtifactsDirectory != "" {
+		args = append(args, "--junitxml", filepath.Join(opts.ArtifactsDirectory, JUnitFileName))
+	}
+	if opts.Markers != "" {
+		args = append(args, "-m", opts.Markers)
+	}
+	return append(args, opts.Args...)
+}
+
+// Env returns the environment variables wiring the test-infra and controller
+// test packages together.
+func (r *Runner) Env(repo *repository.Repository, opts Options) []string {
+	pythonPath := []string{
+		filepath.Join(r.testInfraPath, testInfraSourceDirectory),
+		filepath.Join(repo.FullPath, "test"),
+	}
+	if existing := os.Getenv("PYTHONPATH"); existing != "" {
+		pythonPath = append(pythonPath, existing)
+	}
+
+	env := []string{"PYTHONPATH=" + strings.Join(pythonPath, string(os.PathListSeparator))}
+	if opts.Kubeconfig != "" {
+		env = append(env, "KUBECONFIG="+opts.Kubeconfig)
+	}
+	if region := opts.Flags[awsRegionFlag]; region != "" {
+		env = append(env, "AWS_DEFAULT_REGION="+region, "AWS_REGION="+region)
+	}
+	return env
+}
@@ -0,0 +1,99 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestArtifactsDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-e2e")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repo := &repository.Repository{Name: "s3-controller"}
+	artifactsDir, err := ArtifactsDirectory(dir, repo, time.Date(2021, 6, 1, 13, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "artifacts", "e2e", "s3-controller", "20210601-130405"), artifactsDir)
+
+	info, err := os.Stat(artifactsDir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestRunner_Args(t *testing.T) {
+	r := NewRunner("/ack/test-infra")
+	assert.Equal(t, []string{
+		"-m", "pytest", "-o", "log_cli=true", "--log-cli-level", "INFO",
+	}, r.Args(Options{}))
+	assert.Equal(t, []string{
+		"-m", "pytest", "-o", "log_cli=true", "--log-cli-level", "INFO",
+		"--junitxml", filepath.Join("/artifacts", "junit.xml"),
+		"-m", "not slow",
+		"-k", "bucket",
+	}, r.Args(Options{
+		Markers:            "not slow",
+		Args:               []string{"-k", "bucket"},
+		ArtifactsDirectory: "/artifacts",
+	}))
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-e2e")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Unsetenv("PYTHONPATH"))
+
+	python, err := testutil.NewFakeBinary(dir, "python3", `echo "$PYTHONPATH $KUBECONFIG $AWS_REGION $(pwd)" > "$(dirname "$0")/env"`)
+	require.NoError(t, err)
+	r := NewRunner(filepath.Join(dir, "test-infra"), WithPythonBinary(python))
+
+	repo := &repository.Repository{Name: "s3-controller", FullPath: filepath.Join(dir, "s3-controller")}
+	opts := Options{
+		Markers:    "canary",
+		Kubeconfig: "/tmp/kubeconfig",
+		Flags:      map[string]string{"aws-region": "eu-west-1"},
+	}
+	err = r.Run(context.Background(), repo, opts)
+	assert.True(t, errors.Is(err, ErrMissingTests))
+
+	testDir := filepath.Join(repo.FullPath, "test", "e2e")
+	require.NoError(t, os.MkdirAll(testDir, os.ModePerm))
+	testDir, err = filepath.EvalSymlinks(testDir)
+	require.NoError(t, err)
+	require.NoError(t, r.Run(context.Background(), repo, opts))
+
+	calls, err := testutil.FakeBinaryCalls(dir, "python3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-m pytest -o log_cli=true --log-cli-level INFO -m canary"}, calls)
+
+	env, err := ioutil.ReadFile(filepath.Join(dir, "env"))
+	require.NoError(t, err)
+	pythonPath := filepath.Join(dir, "test-infra", "src") + ":" + filepath.Join(repo.FullPath, "test")
+	assert.Equal(t, pythonPath+" /tmp/kubeconfig eu-west-1 "+testDir+"\n", string(env))
+}
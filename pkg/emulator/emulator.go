@@ -0,0 +1,248 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package emulator runs a local AWS emulator, such as localstack or moto, used
+// to run service controllers without an AWS account.
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// DefaultHealthTimeout is the time given to the emulator to become
+	// healthy.
+	DefaultHealthTimeout = time.Minute
+
+	// Credentials, region and account ID passed to the controllers using the
+	// emulator. Emulators accept any credentials.
+	AccessKeyID     = "test"
+	SecretAccessKey = "test"
+	DefaultRegion   = "us-east-1"
+	DefaultAccount  = "000000000000"
+
+	endpointURLFlag     = "aws-endpoint-url"
+	regionFlag          = "aws-region"
+	accountIDFlag       = "aws-account-id"
+	containerNamePrefix = "ackdev-emulator-"
+	healthCheckInterval = 500 * time.Millisecond
+	healthCheckTimeout  = 2 * time.Second
+)
+
+var (
+	ErrNotHealthy error = errors.New("emulator is not healthy")
+	ErrExited     error = errors.New("emulator exited")
+)
+
+// Option is a function modifying an Emulator.
+type Option func(*Emulator)
+
+// WithDockerBinary sets the docker binary running container emulators.
+func WithDockerBinary(path string) Option {
+	return func(e *Emulator) {
+		e.docker.Binary = path
+	}
+}
+
+// WithOutput streams the output of the emulator to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(e *Emulator) {
+		e.out = out
+	}
+}
+
+// WithHealthTimeout sets the time given to the emulator to become healthy.
+func WithHealthTimeout(d time.Duration) Option {
+	return func(e *Emulator) {
+		e.healthTimeout = d
+	}
+}
+
+// Emulator is a local AWS emulator, running either in a container or as a
+// local process.
+type Emulator struct {
+	cfg           config.EmulatorConfig
+	docker        *tools.Tool
+	out           asyncexec.Output
+	healthTimeout time.Duration
+	containerName string
+
+	cmd    *asyncexec.Cmd
+	exited chan struct{}
+	err    error
+}
+
+// New instantiates a new Emulator.
+func New(cfg config.EmulatorConfig, opts ...Option) *Emulator {
+	e := &Emulator{
+		cfg:           cfg,
+		docker:        tools.New("docker"),
+		healthTimeout: DefaultHealthTimeout,
+		containerName: containerNamePrefix + strconv.Itoa(os.Getpid()),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Endpoint returns the URL of the emulator.
+func (e *Emulator) Endpoint() string {
+	return fmt.Sprintf("http://localhost:%d", e.cfg.Port)
+}
+
+// Flags returns a copy of the controller flags pointing to the emulator. The
+// region and account ID are set to default values if they are missing.
+func (e *Emulator) Flags(flags map[string]string) map[string]string {
+	emulatorFlags := map[string]string{
+		regionFlag:    DefaultRegion,
+		accountIDFlag: DefaultAccount,
+	}
+	for k, v := range flags {
+		emulatorFlags[k] = v
+	}
+	emulatorFlags[endpointURLFlag] = e.Endpoint()
+	return emulatorFlags
+}
+
+// Env returns the environment variables of the controllers using the
+// emulator, containing dummy credentials.
+func Env() []string {
+	return []string{
+		"AWS_ACCESS_KEY_ID=" + AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + SecretAccessKey,
+		"AWS_SESSION_TOKEN=",
+	}
+}
+
+// command returns the command running the emulator.
+func (e *Emulator) command() *exec.Cmd {
+	env := make([]string, 0, len(e.cfg.Env))
+	for k, v := range e.cfg.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+
+	if e.cfg.Image != "" {
+		port := strconv.Itoa(e.cfg.Port)
+		args := []string{"run", "--rm", "--name", e.containerName, "--publish", port + ":" + port}
+		for _, kv := range env {
+			args = append(args, "--env", kv)
+		}
+		args = append(args, e.cfg.Image)
+		return exec.Command(e.docker.Binary, args...)
+	}
+
+	cmd := exec.Command(e.cfg.Command[0], e.cfg.Command[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// Start starts the emulator and waits until it is healthy. If the emulator
+// doesn't become healthy it is stopped.
+func (e *Emulator) Start(ctx context.Context) error {
+	opts := []asyncexec.Option{}
+	if e.out != nil {
+		opts = append(opts, asyncexec.WithOutput(e.out))
+	}
+	e.cmd = asyncexec.New(e.command(), opts...)
+	err := e.cmd.Start()
+	if err != nil {
+		return err
+	}
+	e.exited = make(chan struct{})
+	go func() {
+		_, e.err = e.cmd.Wait()
+		close(e.exited)
+	}()
+
+	err = e.waitHealthy(ctx)
+	if err != nil {
+		e.Stop(context.Background())
+		return err
+	}
+	return nil
+}
+
+// waitHealthy polls the emulator health path until it answers with a success
+// status. Answers received after the emulator exited are ignored, since they
+// come from another process listening on the emulator port.
+func (e *Emulator) waitHealthy(ctx context.Context) error {
+	url := e.Endpoint() + e.cfg.HealthPath
+	client := &http.Client{Timeout: healthCheckTimeout}
+	timeout := time.NewTimer(e.healthTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices && e.running() {
+				return nil
+			}
+		}
+
+		select {
+		case <-e.exited:
+			return fmt.Errorf("%w before being healthy: %v", ErrExited, e.err)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return fmt.Errorf("%w: %s didn't answer within %s", ErrNotHealthy, url, e.healthTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// running returns true if the emulator hasn't exited.
+func (e *Emulator) running() bool {
+	select {
+	case <-e.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// Exited returns a channel closed when the emulator exits.
+func (e *Emulator) Exited() <-chan struct{} {
+	return e.exited
+}
+
+// Stop stops the emulator and waits for it to exit. Container emulators are
+// removed.
+func (e *Emulator) Stop(ctx context.Context) error {
+	if e.cmd == nil {
+		return nil
+	}
+	var err error
+	if e.cfg.Image != "" {
+		err = e.docker.Run(ctx, nil, "rm", "--force", e.containerName)
+	}
+	e.cmd.Stop()
+	<-e.exited
+	return err
+}
@@ -0,0 +1,195 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+// newHealthServer starts an HTTP server answering the health checks, and
+// returns its port.
+func newHealthServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, int) {
+	server := httptest.NewServer(handler)
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return server, p
+}
+
+func TestEmulator_Flags(t *testing.T) {
+	e := New(config.EmulatorConfig{Image: "localstack/localstack", Port: 4566})
+	assert.Equal(t, "http://localhost:4566", e.Endpoint())
+
+	flags := map[string]string{"aws-region": "eu-west-2", "log-level": "debug"}
+	assert.Equal(t, map[string]string{
+		"aws-region":       "eu-west-2",
+		"aws-account-id":   "000000000000",
+		"aws-endpoint-url": "http://localhost:4566",
+		"log-level":        "debug",
+	}, e.Flags(flags))
+	// the configured flags are not modified
+	assert.Len(t, flags, 2)
+}
+
+func TestEmulator_Process(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-emulator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the emulator is healthy once it wrote its environment
+	envPath := filepath.Join(dir, "env")
+	server, port := newHealthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		if _, err := os.Stat(envPath); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	binary, err := testutil.NewFakeBinary(dir, "moto_server", `echo "SERVICES=$SERVICES" > "$(dirname "$0")/env"
+exec sleep 30`)
+	require.NoError(t, err)
+
+	e := New(config.EmulatorConfig{
+		Command:    []string{binary, "-p", strconv.Itoa(port)},
+		Port:       port,
+		HealthPath: "/health",
+		Env:        map[string]string{"SERVICES": "s3"},
+	})
+	require.NoError(t, e.Start(context.Background()))
+
+	select {
+	case <-e.Exited():
+		t.Fatal("emulator exited")
+	default:
+	}
+	require.NoError(t, e.Stop(context.Background()))
+	<-e.Exited()
+
+	calls, err := testutil.FakeBinaryCalls(dir, "moto_server")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-p " + strconv.Itoa(port)}, calls)
+	env, err := ioutil.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Equal(t, "SERVICES=s3\n", string(env))
+}
+
+func TestEmulator_Container(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-emulator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	startedPath := filepath.Join(dir, "started")
+	server, port := newHealthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, err := os.Stat(startedPath); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	docker, err := testutil.NewFakeBinary(dir, "docker", `if [ "$1" = "run" ]; then
+  touch "$(dirname "$0")/started"
+  exec sleep 30
+fi`)
+	require.NoError(t, err)
+
+	e := New(config.EmulatorConfig{
+		Image: "localstack/localstack",
+		Port:  port,
+		Env:   map[string]string{"SERVICES": "s3", "DEBUG": "1"},
+	}, WithDockerBinary(docker))
+	require.NoError(t, e.Start(context.Background()))
+	require.NoError(t, e.Stop(context.Background()))
+
+	calls, err := testutil.FakeBinaryCalls(dir, "docker")
+	require.NoError(t, err)
+	p := strconv.Itoa(port)
+	assert.Equal(t, []string{
+		"run --rm --name " + e.containerName + " --publish " + p + ":" + p + " --env DEBUG=1 --env SERVICES=s3 localstack/localstack",
+		"rm --force " + e.containerName,
+	}, calls)
+}
+
+func TestEmulator_Start_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-emulator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server, port := newHealthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	exiting, err := testutil.NewFakeBinary(dir, "exiting", "exit 3")
+	require.NoError(t, err)
+	e := New(config.EmulatorConfig{Command: []string{exiting}, Port: port})
+	err = e.Start(context.Background())
+	assert.True(t, errors.Is(err, ErrExited), err)
+
+	sleeping, err := testutil.NewFakeBinary(dir, "sleeping", "exec sleep 30")
+	require.NoError(t, err)
+	e = New(config.EmulatorConfig{Command: []string{sleeping}, Port: port}, WithHealthTimeout(time.Second))
+	err = e.Start(context.Background())
+	assert.True(t, errors.Is(err, ErrNotHealthy), err)
+	// the emulator was stopped
+	<-e.Exited()
+
+	// only success statuses are healthy
+	notFoundServer, notFoundPort := newHealthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer notFoundServer.Close()
+	e = New(config.EmulatorConfig{Command: []string{sleeping}, Port: notFoundPort}, WithHealthTimeout(time.Second))
+	err = e.Start(context.Background())
+	assert.True(t, errors.Is(err, ErrNotHealthy), err)
+	<-e.Exited()
+}
+
+func TestEmulator_Start_PortInUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-emulator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// another process answers on the emulator port, after the emulator failed
+	// to listen on it and exited
+	server, port := newHealthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	defer server.Close()
+
+	exiting, err := testutil.NewFakeBinary(dir, "exiting", "echo 'address already in use' >&2; exit 1")
+	require.NoError(t, err)
+	e := New(config.EmulatorConfig{Command: []string{exiting}, Port: port})
+	err = e.Start(context.Background())
+	assert.True(t, errors.Is(err, ErrExited), err)
+}
@@ -15,12 +15,17 @@ package git
 
 import (
 	"context"
+	"fmt"
 
 	"golang.org/x/crypto/ssh"
 	"gopkg.in/src-d/go-git.v4"
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
 )
 
 var _ OpenCloner = &Git{}
@@ -69,6 +74,7 @@ func New(options ...Option) *Git {
 // Git implements OpenCloner interface.
 type Git struct {
 	signer         ssh.Signer
+	sshKeyPath     string
 	remote         string
 	githubToken    string
 	githubUsername string
@@ -77,19 +83,11 @@ type Git struct {
 // Clone clones a remote git repository into a destination path. Clone will
 // prioritise SSH signer if it's set.
 func (g *Git) Clone(ctx context.Context, url, dest string) error {
-	var auth transport.AuthMethod
-	if g.signer != nil {
-		auth = &gitssh.PublicKeys{
-			User:   defaultUser,
-			Signer: g.signer,
-		}
-	} else {
-		auth = &githttp.BasicAuth{
-			Password: g.githubToken,
-			Username: g.githubUsername,
-		}
+	auth, err := g.auth()
+	if err != nil {
+		return err
 	}
-	_, err := git.PlainCloneContext(ctx, dest, false, &git.CloneOptions{
+	_, err = git.PlainCloneContext(ctx, dest, false, &git.CloneOptions{
 		Auth:       auth,
 		URL:        url,
 		RemoteName: g.remote,
@@ -101,6 +99,43 @@ func (g *Git) Clone(ctx context.Context, url, dest string) error {
 	return nil
 }
 
+// CheckAccess lists the references of a remote git repository to verify that
+// the configured authentication method is accepted by the git transport.
+func (g *Git) CheckAccess(url string) error {
+	auth, err := g.auth()
+	if err != nil {
+		return err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+		Name: g.remote,
+		URLs: []string{url},
+	})
+	_, err = remote.List(&git.ListOptions{Auth: auth})
+	return err
+}
+
+// auth returns the transport authentication method. If an SSH key path is
+// configured, the signer is lazily loaded the first time it is needed.
+func (g *Git) auth() (transport.AuthMethod, error) {
+	if g.signer == nil && g.sshKeyPath != "" {
+		signer, err := util.NewSigner(g.sshKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load ssh key %s: %v", g.sshKeyPath, err)
+		}
+		g.signer = signer
+	}
+	if g.signer != nil {
+		return &gitssh.PublicKeys{
+			User:   defaultUser,
+			Signer: g.signer,
+		}, nil
+	}
+	return &githttp.BasicAuth{
+		Password: g.githubToken,
+		Username: g.githubUsername,
+	}, nil
+}
+
 // Open opens a git repository from the given path.
 func (g *Git) Open(path string) (*git.Repository, error) {
 	return git.PlainOpen(path)
@@ -40,3 +40,11 @@ func WithSSHSigner(signer ssh.Signer) Option {
 		g.signer = signer
 	}
 }
+
+// WithSSHKeyPath sets the path of the private key used to clone repositories
+// with ssh protocol. The key is only read when it is first needed.
+func WithSSHKeyPath(path string) Option {
+	return func(g *Git) {
+		g.sshKeyPath = path
+	}
+}
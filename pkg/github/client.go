@@ -16,6 +16,7 @@ package github
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v35/github"
@@ -31,8 +32,12 @@ const (
 	defaultRequestTimeout = 10 * time.Second
 )
 
-// NewClient takes a token and instantiate a new Client object
+// NewClient takes a token and instantiate a new Client object. If the token
+// is empty the client will make unauthenticated requests.
 func NewClient(token string) *Client {
+	if token == "" {
+		return &Client{github.NewClient(nil)}
+	}
 	ctx := context.TODO()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
@@ -44,11 +49,12 @@ func NewClient(token string) *Client {
 // RepositoryService is the interface implemented by the Github client wrapper. It exposes
 // functionalities to simplify the interactions with the repository endpoint of Github APIv3
 type RepositoryService interface {
-	ForkRepository(ctx context.Context, repoName string) error
+	ForkRepository(ctx context.Context, owner, repoName string) error
 	RenameRepository(ctx context.Context, owner, name, newName string) error
 	GetRepository(ctx context.Context, owner, repoName string) (*github.Repository, error)
-	ListRepositoryForks(ctx context.Context, repoName string) ([]*github.Repository, error)
-	GetUserRepositoryFork(ctx context.Context, owner, repoName string) (*github.Repository, error)
+	ListRepositoryForks(ctx context.Context, owner, repoName string) ([]*github.Repository, error)
+	GetUserRepositoryFork(ctx context.Context, user, owner, repoName string) (*github.Repository, error)
+	DeleteRepository(ctx context.Context, owner, repoName string) error
 }
 
 // Client is a github.Client wrapper
@@ -56,13 +62,13 @@ type Client struct {
 	*github.Client
 }
 
-// ForkRepository forks a Github repository from the ACK organisation.
-func (c *Client) ForkRepository(ctx context.Context, repoName string) error {
+// ForkRepository forks a Github repository, generally owned by the ACK organisation.
+func (c *Client) ForkRepository(ctx context.Context, owner, repoName string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancel()
 
 	opt := &github.RepositoryCreateForkOptions{}
-	_, _, err := c.Client.Repositories.CreateFork(ctx, ACKOrg, repoName, opt)
+	_, _, err := c.Client.Repositories.CreateFork(ctx, owner, repoName, opt)
 	if err != nil {
 		// AcceptedError occurs when GitHub returns 202 Accepted response with an
 		// empty body, which means a job was scheduled on the GitHub side to process
@@ -92,6 +98,19 @@ func (c *Client) RenameRepository(ctx context.Context, owner, name, newName stri
 	return nil
 }
 
+// DeleteRepository deletes a Github repository. The token needs the 'delete_repo'
+// scope to be able to delete repositories.
+func (c *Client) DeleteRepository(ctx context.Context, owner, repoName string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
+	_, err := c.Client.Repositories.Delete(ctx, owner, repoName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // GetRepository takes an owner and repoName and returns the Github repository informations
 func (c *Client) GetRepository(ctx context.Context, owner, repoName string) (*github.Repository, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
@@ -104,9 +123,10 @@ func (c *Client) GetRepository(ctx context.Context, owner, repoName string) (*gi
 	return repo, nil
 }
 
-// ListRepositoryForks list the forks of a given repository in the ACK organisation. It returns
-// a list fork information which includes the owner and the fork name (forkInfo).
-func (c *Client) ListRepositoryForks(ctx context.Context, repoName string) ([]*github.Repository, error) {
+// ListRepositoryForks list the forks of a given repository, generally owned by the ACK
+// organisation. It returns a list fork information which includes the owner and the fork
+// name (forkInfo).
+func (c *Client) ListRepositoryForks(ctx context.Context, owner, repoName string) ([]*github.Repository, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancel()
 
@@ -129,7 +149,7 @@ func (c *Client) ListRepositoryForks(ctx context.Context, repoName string) ([]*g
 			},
 		}
 
-		repos, resp, err = c.Client.Repositories.ListForks(ctx, ACKOrg, repoName, opt)
+		repos, resp, err = c.Client.Repositories.ListForks(ctx, owner, repoName, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -140,20 +160,87 @@ func (c *Client) ListRepositoryForks(ctx context.Context, repoName string) ([]*g
 	return forks, nil
 }
 
-// GetUserRepositoryFork takes an ACK repository name and tries to find it fork in the user public repositories.
-func (c *Client) GetUserRepositoryFork(ctx context.Context, owner string, repoName string) (*github.Repository, error) {
+// GetUserRepositoryFork takes an ACK repository owner and name and tries to find it fork in the user
+// public repositories.
+func (c *Client) GetUserRepositoryFork(ctx context.Context, user, owner, repoName string) (*github.Repository, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancel()
 
-	repos, err := c.ListRepositoryForks(ctx, repoName)
+	repos, err := c.ListRepositoryForks(ctx, owner, repoName)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, repo := range repos {
-		if *repo.Owner.Login == owner {
+		if *repo.Owner.Login == user {
 			return repo, nil
 		}
 	}
 	return nil, ErrForkNotFound
 }
+
+// GetAuthenticatedUser returns the Github user owning the client token. It is
+// mainly used to verify that a token is valid.
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (*github.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
+	user, _, err := c.Client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// TokenScopes returns the OAuth scopes of the client token. Fine-grained
+// tokens don't have scopes, in which case the returned list is empty.
+func (c *Client) TokenScopes(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
+	_, resp, err := c.Client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	scopes := []string{}
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// ListOrganizationRepositories lists all the repositories of a given Github
+// organisation.
+func (c *Client) ListOrganizationRepositories(ctx context.Context, org string) ([]*github.Repository, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
+	var repositories []*github.Repository
+	var err error
+	var repos []*github.Repository
+	var resp *github.Response = &github.Response{
+		// FirstPage is always of index 1
+		NextPage: 1,
+	}
+
+	// iterate over all the pages
+	for resp.NextPage != 0 {
+		opt := &github.RepositoryListByOrgOptions{
+			ListOptions: github.ListOptions{
+				Page:    resp.NextPage,
+				PerPage: 100,
+			},
+		}
+
+		repos, resp, err = c.Client.Repositories.ListByOrg(ctx, org, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		repositories = append(repositories, repos...)
+	}
+
+	return repositories, nil
+}
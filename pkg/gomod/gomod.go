@@ -0,0 +1,190 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package gomod reads the go.mod files of the ACK repositories. It only
+// supports the directives ackdev needs: module, go, require and replace.
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FileName is the name of the go module files.
+	FileName = "go.mod"
+
+	indirectComment = "// indirect"
+)
+
+// Require is a module requirement.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// Replace is a module replacement. NewVersion is empty when the module is
+// replaced by a local directory.
+type Replace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// File is a parsed go.mod file.
+type File struct {
+	// Module is the module path.
+	Module string
+	// Go is the version of the go directive, e.g 1.17.
+	Go       string
+	Requires []Require
+	Replaces []Replace
+}
+
+// ReadFile reads and parses the go.mod file of a directory.
+func ReadFile(dir string) (*File, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses the content of a go.mod file.
+func Parse(content []byte) (*File, error) {
+	f := &File{}
+	block := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		indirect := strings.HasSuffix(line, indirectComment)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			if err := f.add(block, strings.Fields(line), indirect); err != nil {
+				return nil, fmt.Errorf("go.mod:%d: %w", n, err)
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+		if err := f.add(fields[0], fields[1:], indirect); err != nil {
+			return nil, fmt.Errorf("go.mod:%d: %w", n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if block != "" {
+		return nil, fmt.Errorf("go.mod: unterminated %s block", block)
+	}
+	return f, nil
+}
+
+// add adds a directive to the file. Unsupported directives are ignored.
+func (f *File) add(verb string, args []string, indirect bool) error {
+	for i, arg := range args {
+		if unquoted, err := strconv.Unquote(arg); err == nil {
+			args[i] = unquoted
+		}
+	}
+	switch verb {
+	case "module":
+		if len(args) != 1 {
+			return fmt.Errorf("invalid module directive")
+		}
+		f.Module = args[0]
+	case "go":
+		if len(args) != 1 {
+			return fmt.Errorf("invalid go directive")
+		}
+		f.Go = args[0]
+	case "require":
+		if len(args) != 2 {
+			return fmt.Errorf("invalid require directive")
+		}
+		f.Requires = append(f.Requires, Require{Path: args[0], Version: args[1], Indirect: indirect})
+	case "replace":
+		r := Replace{}
+		switch {
+		case len(args) >= 3 && args[1] == "=>":
+			r.OldPath, args = args[0], args[2:]
+		case len(args) >= 4 && args[2] == "=>":
+			r.OldPath, r.OldVersion, args = args[0], args[1], args[3:]
+		default:
+			return fmt.Errorf("invalid replace directive")
+		}
+		switch len(args) {
+		case 1:
+			r.NewPath = args[0]
+		case 2:
+			r.NewPath, r.NewVersion = args[0], args[1]
+		default:
+			return fmt.Errorf("invalid replace directive")
+		}
+		f.Replaces = append(f.Replaces, r)
+	}
+	return nil
+}
+
+// Require returns the requirement of a module, or nil if the module is not
+// required.
+func (f *File) Require(path string) *Require {
+	for i := range f.Requires {
+		if f.Requires[i].Path == path {
+			return &f.Requires[i]
+		}
+	}
+	return nil
+}
+
+// Replace returns the replacement of a module, or nil if the module is not
+// replaced.
+func (f *File) Replace(path string) *Replace {
+	for i := range f.Replaces {
+		if f.Replaces[i].OldPath == path {
+			return &f.Replaces[i]
+		}
+	}
+	return nil
+}
+
+// Version returns the required version of a module, or an empty string if the
+// module is not required.
+func (f *File) Version(path string) string {
+	r := f.Require(path)
+	if r == nil {
+		return ""
+	}
+	return r.Version
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package gomod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleGoMod = `module github.com/aws-controllers-k8s/s3-controller
+
+go 1.17
+
+require (
+	github.com/aws-controllers-k8s/runtime v0.15.2
+	github.com/aws/aws-sdk-go v1.42.0
+	github.com/go-logr/logr v1.2.0 // indirect
+)
+
+require "k8s.io/api" v0.23.0 // a comment
+
+replace github.com/aws-controllers-k8s/runtime => ../runtime
+
+replace (
+	k8s.io/client-go v0.23.0 => k8s.io/client-go v0.23.1
+)
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sampleGoMod))
+	require.NoError(t, err)
+
+	assert.Equal(t, "github.com/aws-controllers-k8s/s3-controller", f.Module)
+	assert.Equal(t, "1.17", f.Go)
+	assert.Equal(t, []Require{
+		{Path: "github.com/aws-controllers-k8s/runtime", Version: "v0.15.2"},
+		{Path: "github.com/aws/aws-sdk-go", Version: "v1.42.0"},
+		{Path: "github.com/go-logr/logr", Version: "v1.2.0", Indirect: true},
+		{Path: "k8s.io/api", Version: "v0.23.0"},
+	}, f.Requires)
+	assert.Equal(t, []Replace{
+		{OldPath: "github.com/aws-controllers-k8s/runtime", NewPath: "../runtime"},
+		{OldPath: "k8s.io/client-go", OldVersion: "v0.23.0", NewPath: "k8s.io/client-go", NewVersion: "v0.23.1"},
+	}, f.Replaces)
+
+	assert.Equal(t, "v1.42.0", f.Version("github.com/aws/aws-sdk-go"))
+	assert.Equal(t, "", f.Version("github.com/unknown/module"))
+	assert.Equal(t, "../runtime", f.Replace("github.com/aws-controllers-k8s/runtime").NewPath)
+	assert.Nil(t, f.Replace("github.com/aws/aws-sdk-go"))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unterminated block", content: "require (\n\tk8s.io/api v0.23.0\n"},
+		{name: "invalid require", content: "require k8s.io/api\n"},
+		{name: "invalid replace", content: "replace k8s.io/api v0.23.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-gomod")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = ReadFile(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, FileName), []byte(sampleGoMod), 0644))
+	f, err := ReadFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.15.2", f.Version("github.com/aws-controllers-k8s/runtime"))
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package gotest
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Name    string           `xml:"name,attr,omitempty"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in the JUnit XML format. Each package is a test
+// suite, and packages that failed without running any test are reported as a
+// failed test case.
+func (r *Report) WriteJUnit(w io.Writer, name string) error {
+	suites := junitTestSuites{Name: name, Suites: []junitTestSuite{}}
+	for _, pkg := range r.Packages() {
+		suite := junitTestSuite{
+			Name:  pkg.Name,
+			Time:  formatSeconds(pkg.Elapsed.Seconds()),
+			Cases: []junitTestCase{},
+		}
+		if pkg.Status == StatusFail && len(pkg.Tests) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "build",
+				ClassName: pkg.Name,
+				Time:      formatSeconds(0),
+				Failure:   &junitMessage{Message: "package failed", Contents: pkg.Output},
+			})
+		}
+		for _, test := range pkg.Tests {
+			testCase := junitTestCase{
+				Name:      test.Name,
+				ClassName: pkg.Name,
+				Time:      formatSeconds(test.Elapsed.Seconds()),
+			}
+			switch test.Status {
+			case StatusFail:
+				testCase.Failure = &junitMessage{Message: "test failed", Contents: test.Output}
+			case StatusSkip:
+				testCase.Skipped = &junitMessage{Message: "test skipped"}
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		for _, testCase := range suite.Cases {
+			suite.Tests++
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+			if testCase.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
@@ -0,0 +1,235 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package gotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a test or a package.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// event is a go test -json event. See `go doc test2json`.
+type event struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// TestResult is the result of a single test.
+type TestResult struct {
+	Package string
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	// Output is the output of the test. It is only kept for failed tests.
+	Output string
+}
+
+// PackageResult is the result of a package.
+type PackageResult struct {
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	Tests   []*TestResult
+	// Output is the package output that doesn't belong to a test, for example
+	// build errors.
+	Output string
+}
+
+// Report aggregates the results of a go test -json run. It implements
+// asyncexec.Output, so that it can be fed directly by a go test command.
+type Report struct {
+	mu sync.Mutex
+
+	packages map[string]*PackageResult
+	tests    map[string]*TestResult
+	outputs  map[string]*strings.Builder
+	// unparsed contains the lines that are not go test events, for example
+	// build errors written to stderr.
+	unparsed []string
+}
+
+// NewReport returns an empty Report.
+func NewReport() *Report {
+	return &Report{
+		packages: map[string]*PackageResult{},
+		tests:    map[string]*TestResult{},
+		outputs:  map[string]*strings.Builder{},
+	}
+}
+
+// WriteStdout parses a go test -json event.
+func (r *Report) WriteStdout(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var e event
+	if !bytes.HasPrefix(line, []byte("{")) || json.Unmarshal(line, &e) != nil {
+		r.unparsed = append(r.unparsed, string(line))
+		return nil
+	}
+	r.handle(&e)
+	return nil
+}
+
+// WriteStderr records lines written to stderr, such as build errors.
+func (r *Report) WriteStderr(line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unparsed = append(r.unparsed, string(line))
+	return nil
+}
+
+func (r *Report) handle(e *event) {
+	pkg, ok := r.packages[e.Package]
+	if !ok {
+		pkg = &PackageResult{Name: e.Package, Tests: []*TestResult{}}
+		r.packages[e.Package] = pkg
+	}
+	key := e.Package + " " + e.Test
+	if _, ok := r.outputs[key]; !ok {
+		r.outputs[key] = &strings.Builder{}
+	}
+
+	if e.Test == "" {
+		switch e.Action {
+		case "output":
+			r.outputs[key].WriteString(e.Output)
+		case "pass", "fail", "skip":
+			pkg.Status = Status(e.Action)
+			pkg.Elapsed = seconds(e.Elapsed)
+			if pkg.Status == StatusFail {
+				pkg.Output = r.outputs[key].String()
+			}
+		}
+		return
+	}
+
+	test, ok := r.tests[key]
+	if !ok {
+		test = &TestResult{Package: e.Package, Name: e.Test}
+		r.tests[key] = test
+		pkg.Tests = append(pkg.Tests, test)
+	}
+	switch e.Action {
+	case "output":
+		r.outputs[key].WriteString(e.Output)
+	case "pass", "fail", "skip":
+		test.Status = Status(e.Action)
+		test.Elapsed = seconds(e.Elapsed)
+		if test.Status == StatusFail {
+			test.Output = r.outputs[key].String()
+		}
+		delete(r.outputs, key)
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Packages returns the package results, sorted by name.
+func (r *Report) Packages() []*PackageResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	packages := make([]*PackageResult, 0, len(r.packages))
+	for _, pkg := range r.packages {
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// Tests returns the results of all the tests. Subtests are included.
+func (r *Report) Tests() []*TestResult {
+	tests := []*TestResult{}
+	for _, pkg := range r.Packages() {
+		tests = append(tests, pkg.Tests...)
+	}
+	return tests
+}
+
+// Counts returns the number of passed, failed and skipped tests. Packages
+// that failed without running any test, for example because of build errors,
+// are counted as failed tests.
+func (r *Report) Counts() (passed, failed, skipped int) {
+	for _, pkg := range r.Packages() {
+		if pkg.Status == StatusFail && len(pkg.Tests) == 0 {
+			failed++
+		}
+		for _, test := range pkg.Tests {
+			switch test.Status {
+			case StatusPass:
+				passed++
+			case StatusFail:
+				failed++
+			case StatusSkip:
+				skipped++
+			}
+		}
+	}
+	return passed, failed, skipped
+}
+
+// Failed returns true if any package or test failed, or if the output
+// couldn't be parsed at all.
+func (r *Report) Failed() bool {
+	packages := r.Packages()
+	if len(packages) == 0 {
+		return len(r.Unparsed()) > 0
+	}
+	for _, pkg := range packages {
+		if pkg.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Slowest returns the n slowest tests, slowest first.
+func (r *Report) Slowest(n int) []*TestResult {
+	tests := r.Tests()
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].Elapsed > tests[j].Elapsed
+	})
+	if len(tests) > n {
+		tests = tests[:n]
+	}
+	return tests
+}
+
+// Unparsed returns the output lines that are not go test events.
+func (r *Report) Unparsed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.unparsed...)
+}
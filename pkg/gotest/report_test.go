@@ -0,0 +1,133 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package gotest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleOutput = `{"Action":"run","Package":"example.com/a","Test":"TestPass"}
+{"Action":"output","Package":"example.com/a","Test":"TestPass","Output":"=== RUN   TestPass\n"}
+{"Action":"pass","Package":"example.com/a","Test":"TestPass","Elapsed":0.5}
+{"Action":"run","Package":"example.com/a","Test":"TestFail"}
+{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"    a_test.go:10: boom\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestFail","Elapsed":1.25}
+{"Action":"run","Package":"example.com/a","Test":"TestSkip"}
+{"Action":"skip","Package":"example.com/a","Test":"TestSkip","Elapsed":0}
+{"Action":"output","Package":"example.com/a","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/a","Elapsed":2}
+{"Action":"run","Package":"example.com/b","Test":"TestSlow"}
+{"Action":"pass","Package":"example.com/b","Test":"TestSlow","Elapsed":3}
+{"Action":"pass","Package":"example.com/b","Elapsed":3.1}
+{"Action":"output","Package":"example.com/c","Output":"FAIL\texample.com/c [build failed]\n"}
+{"Action":"fail","Package":"example.com/c","Elapsed":0}
+# example.com/c
+not a json line`
+
+func newSampleReport(t *testing.T) *Report {
+	r := NewReport()
+	for _, line := range strings.Split(sampleOutput, "\n") {
+		require.NoError(t, r.WriteStdout([]byte(line)))
+	}
+	require.NoError(t, r.WriteStderr([]byte("c.go:3:1: syntax error")))
+	return r
+}
+
+func TestReport(t *testing.T) {
+	r := newSampleReport(t)
+
+	passed, failed, skipped := r.Counts()
+	assert.Equal(t, 2, passed)
+	assert.Equal(t, 2, failed)
+	assert.Equal(t, 1, skipped)
+	assert.True(t, r.Failed())
+
+	packages := r.Packages()
+	require.Len(t, packages, 3)
+	assert.Equal(t, "example.com/a", packages[0].Name)
+	assert.Equal(t, StatusFail, packages[0].Status)
+	assert.Equal(t, 2*time.Second, packages[0].Elapsed)
+	assert.Equal(t, StatusPass, packages[1].Status)
+	assert.Equal(t, "FAIL\texample.com/c [build failed]\n", packages[2].Output)
+
+	tests := r.Tests()
+	require.Len(t, tests, 4)
+	assert.Equal(t, "    a_test.go:10: boom\n", tests[1].Output)
+	assert.Empty(t, tests[0].Output)
+
+	slowest := r.Slowest(2)
+	require.Len(t, slowest, 2)
+	assert.Equal(t, "TestSlow", slowest[0].Name)
+	assert.Equal(t, "TestFail", slowest[1].Name)
+	assert.Len(t, r.Slowest(10), 4)
+
+	assert.Equal(t, []string{
+		"# example.com/c",
+		"not a json line",
+		"c.go:3:1: syntax error",
+	}, r.Unparsed())
+}
+
+func TestReport_Failed(t *testing.T) {
+	r := NewReport()
+	assert.False(t, r.Failed())
+
+	require.NoError(t, r.WriteStdout([]byte(`{"Action":"pass","Package":"example.com/a","Elapsed":0.1}`)))
+	assert.False(t, r.Failed())
+
+	// go test couldn't run at all, e.g. because of an invalid go.mod
+	r = NewReport()
+	require.NoError(t, r.WriteStderr([]byte("go: errors parsing go.mod")))
+	assert.True(t, r.Failed())
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	r := newSampleReport(t)
+
+	var b bytes.Buffer
+	require.NoError(t, r.WriteJUnit(&b, "s3-controller"))
+	assert.True(t, strings.HasPrefix(b.String(), xml.Header))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &suites))
+	assert.Equal(t, "s3-controller", suites.Name)
+	require.Len(t, suites.Suites, 3)
+
+	a := suites.Suites[0]
+	assert.Equal(t, "example.com/a", a.Name)
+	assert.Equal(t, 3, a.Tests)
+	assert.Equal(t, 1, a.Failures)
+	assert.Equal(t, 1, a.Skipped)
+	assert.Equal(t, "2.000", a.Time)
+	require.Len(t, a.Cases, 3)
+	assert.Equal(t, "TestFail", a.Cases[1].Name)
+	assert.Equal(t, "example.com/a", a.Cases[1].ClassName)
+	assert.Equal(t, "1.250", a.Cases[1].Time)
+	require.NotNil(t, a.Cases[1].Failure)
+	assert.Equal(t, "    a_test.go:10: boom\n", a.Cases[1].Failure.Contents)
+	assert.NotNil(t, a.Cases[2].Skipped)
+
+	c := suites.Suites[2]
+	assert.Equal(t, 1, c.Tests)
+	assert.Equal(t, 1, c.Failures)
+	require.Len(t, c.Cases, 1)
+	assert.Equal(t, "build", c.Cases[0].Name)
+}
@@ -0,0 +1,76 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package gotest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const artifactsTimeFormat = "20060102-150405"
+
+// ArtifactsDirectory returns a new timestamped directory where the reports of
+// a unit test run are collected:
+// <stateDirectory>/artifacts/unit/<timestamp>
+func ArtifactsDirectory(stateDirectory string, t time.Time) (string, error) {
+	dir := filepath.Join(stateDirectory, "artifacts", "unit", t.Format(artifactsTimeFormat))
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Option is a function modifying a Runner.
+type Option func(*Runner)
+
+// WithGoBinary sets the go binary running the tests.
+func WithGoBinary(path string) Option {
+	return func(r *Runner) {
+		r.goBinary = path
+	}
+}
+
+// Runner runs the unit tests of local repositories.
+type Runner struct {
+	goBinary string
+}
+
+// NewRunner instantiates a new Runner.
+func NewRunner(opts ...Option) *Runner {
+	r := &Runner{
+		goBinary: "go",
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run runs go test -json on all the packages of a repository and returns the
+// aggregated report. args are passed to go test, before the package pattern.
+// The report is returned even if the tests failed, along with the go test
+// error.
+func (r *Runner) Run(ctx context.Context, repo *repository.Repository, args ...string) (*Report, error) {
+	report := NewReport()
+	testArgs := append([]string{"test", "-json"}, args...)
+	testArgs = append(testArgs, "./...")
+	err := tools.New(r.goBinary).InDir(repo.FullPath).Run(ctx, report, testArgs...)
+	return report, err
+}
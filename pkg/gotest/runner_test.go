@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package gotest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestRunner_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-gotest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goBinary, err := testutil.NewFakeBinary(dir, "go", `cat <<EOF
+{"Action":"run","Package":"example.com/a","Test":"TestFail"}
+{"Action":"fail","Package":"example.com/a","Test":"TestFail","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/a","Elapsed":0.2}
+EOF
+exit 1`)
+	require.NoError(t, err)
+
+	repo := &repository.Repository{Name: "s3-controller", FullPath: dir}
+	report, err := NewRunner(WithGoBinary(goBinary)).Run(context.Background(), repo, "-race")
+	assert.Error(t, err)
+	require.NotNil(t, report)
+	assert.True(t, report.Failed())
+	_, failed, _ := report.Counts()
+	assert.Equal(t, 1, failed)
+
+	calls, err := testutil.FakeBinaryCalls(dir, "go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test -json -race ./..."}, calls)
+}
+
+func TestArtifactsDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-gotest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	artifactsDir, err := ArtifactsDirectory(dir, time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "artifacts", "unit", "20210601-103000"), artifactsDir)
+	assert.DirExists(t, artifactsDir)
+}
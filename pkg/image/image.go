@@ -0,0 +1,160 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+const (
+	// Repository is the repository of the images built by ackdev. Images are
+	// named <Repository>/<service>-controller:<tag>.
+	Repository = "aws-controllers-k8s"
+
+	dockerfileName = "Dockerfile"
+	shortSHALength = 7
+	devTagPrefix   = "dev-"
+	dirtyTagSuffix = "-dirty-"
+)
+
+var (
+	ErrUnexpectedCheckoutName error = errors.New("unexpected checkout directory name")
+)
+
+// Option is a function modifying a Builder.
+type Option func(*Builder)
+
+// WithDockerBinary sets the docker binary invoked by the Builder.
+func WithDockerBinary(path string) Option {
+	return func(b *Builder) {
+		b.docker.Binary = path
+	}
+}
+
+// WithOutput streams the output of docker to out.
+func WithOutput(out asyncexec.Output) Option {
+	return func(b *Builder) {
+		b.out = out
+	}
+}
+
+// Builder builds service controller images from their local checkouts,
+// following the code-generator conventions: the build context is the parent
+// directory of the controller checkout, and the Dockerfile is the controller
+// one if it exists, the code-generator one otherwise.
+type Builder struct {
+	codeGeneratorPath string
+	docker            *tools.Tool
+	out               asyncexec.Output
+}
+
+// NewBuilder instantiates a new Builder using the Dockerfile of the
+// code-generator checkout found at the given path.
+func NewBuilder(codeGeneratorPath string, opts ...Option) *Builder {
+	b := &Builder{
+		codeGeneratorPath: codeGeneratorPath,
+		docker:            tools.New("docker"),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// DevTag returns a deterministic tag for the current state of a repository:
+// dev-<short HEAD SHA>, with a -dirty-<short changes hash> suffix if the
+// repository has uncommitted changes, so that different uncommitted trees get
+// different tags.
+func DevTag(repo *repository.Repository) (string, error) {
+	commit, err := repo.HeadCommit()
+	if err != nil {
+		return "", err
+	}
+	changesHash, err := repo.ChangesHash()
+	if err != nil {
+		return "", err
+	}
+	return devTag(commit, changesHash), nil
+}
+
+func devTag(commit, changesHash string) string {
+	tag := devTagPrefix + commit[:shortSHALength]
+	if changesHash != "" {
+		tag += dirtyTagSuffix + changesHash[:shortSHALength]
+	}
+	return tag
+}
+
+// Name returns the name of the image of a controller with the given tag.
+func Name(repo *repository.Repository, tag string) string {
+	return fmt.Sprintf("%s/%s:%s", Repository, repo.Name, tag)
+}
+
+// Dockerfile returns the Dockerfile used to build a controller image.
+func (b *Builder) Dockerfile(repo *repository.Repository) string {
+	controllerDockerfile := filepath.Join(repo.FullPath, dockerfileName)
+	if _, err := os.Stat(controllerDockerfile); err == nil {
+		return controllerDockerfile
+	}
+	return filepath.Join(b.codeGeneratorPath, dockerfileName)
+}
+
+// Build builds the image of a controller and returns its record. If tag is
+// empty the image is tagged with DevTag.
+func (b *Builder) Build(ctx context.Context, repo *repository.Repository, tag string) (*Record, error) {
+	// the Dockerfile copies the files from <service>-controller
+	if filepath.Base(repo.FullPath) != repo.Name {
+		return nil, fmt.Errorf("%w: %s should be named %s", ErrUnexpectedCheckoutName, repo.FullPath, repo.Name)
+	}
+
+	commit, err := repo.HeadCommit()
+	if err != nil {
+		return nil, err
+	}
+	changesHash, err := repo.ChangesHash()
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" {
+		tag = devTag(commit, changesHash)
+	}
+	image := Name(repo, tag)
+
+	err = b.docker.Run(ctx, b.out,
+		"build",
+		"--file", b.Dockerfile(repo),
+		"--tag", image,
+		"--build-arg", "service_alias="+strings.TrimSuffix(repo.Name, "-controller"),
+		"--build-arg", "service_controller_git_version="+tag,
+		"--build-arg", "service_controller_git_commit="+commit,
+		filepath.Dir(repo.FullPath),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		Image:  image,
+		Commit: commit,
+		Dirty:  changesHash != "",
+	}, nil
+}
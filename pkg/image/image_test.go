@@ -0,0 +1,137 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package image
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/repository"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+// newControllerRepository creates and loads a controller repository in a
+// temporary root directory.
+func newControllerRepository(t *testing.T) (*repository.Repository, func()) {
+	rootDir, err := ioutil.TempDir("", "ackdev-image")
+	require.NoError(t, err)
+
+	_, err = testutil.NewGitRepository(filepath.Join(rootDir, "s3-controller"), nil)
+	require.NoError(t, err)
+
+	cfg := testutil.NewConfig("s3")
+	cfg.RootDirectory = rootDir
+	manager, err := repository.NewManager(cfg)
+	require.NoError(t, err)
+	repo, err := manager.LoadRepository("s3", repository.RepositoryTypeController)
+	require.NoError(t, err)
+	return repo, func() { os.RemoveAll(rootDir) }
+}
+
+func TestDevTag(t *testing.T) {
+	repo, cleanup := newControllerRepository(t)
+	defer cleanup()
+
+	commit, err := repo.HeadCommit()
+	require.NoError(t, err)
+
+	tag, err := DevTag(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-"+commit[:7], tag)
+
+	// the tag is deterministic
+	again, err := DevTag(repo)
+	require.NoError(t, err)
+	assert.Equal(t, tag, again)
+
+	wip := filepath.Join(repo.FullPath, "wip.go")
+	require.NoError(t, ioutil.WriteFile(wip, nil, 0644))
+	changesHash, err := repo.ChangesHash()
+	require.NoError(t, err)
+	tag, err = DevTag(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-"+commit[:7]+"-dirty-"+changesHash[:7], tag)
+
+	// different uncommitted changes get different tags
+	require.NoError(t, ioutil.WriteFile(wip, []byte("package main"), 0644))
+	again, err = DevTag(repo)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, again)
+}
+
+func TestBuilder_Build(t *testing.T) {
+	repo, cleanup := newControllerRepository(t)
+	defer cleanup()
+
+	binDir := filepath.Join(filepath.Dir(repo.FullPath), "bin")
+	require.NoError(t, os.MkdirAll(binDir, os.ModePerm))
+	docker, err := testutil.NewFakeBinary(binDir, "docker", "")
+	require.NoError(t, err)
+
+	codeGeneratorPath := filepath.Join(filepath.Dir(repo.FullPath), "code-generator")
+	b := NewBuilder(codeGeneratorPath, WithDockerBinary(docker))
+
+	commit, err := repo.HeadCommit()
+	require.NoError(t, err)
+	record, err := b.Build(context.Background(), repo, "")
+	require.NoError(t, err)
+	assert.Equal(t, &Record{
+		Image:  "aws-controllers-k8s/s3-controller:dev-" + commit[:7],
+		Commit: commit,
+	}, record)
+
+	calls, err := testutil.FakeBinaryCalls(binDir, "docker")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"build --file " + filepath.Join(codeGeneratorPath, "Dockerfile") +
+			" --tag aws-controllers-k8s/s3-controller:dev-" + commit[:7] +
+			" --build-arg service_alias=s3" +
+			" --build-arg service_controller_git_version=dev-" + commit[:7] +
+			" --build-arg service_controller_git_commit=" + commit +
+			" " + filepath.Dir(repo.FullPath),
+	}, calls)
+
+	// controllers can have their own Dockerfile
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repo.FullPath, "Dockerfile"), nil, 0644))
+	assert.Equal(t, filepath.Join(repo.FullPath, "Dockerfile"), b.Dockerfile(repo))
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-image")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewStore(filepath.Join(dir, "state"))
+	record, err := store.Get("s3-controller")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	s3Record := &Record{Image: "aws-controllers-k8s/s3-controller:dev-1a2b3c4", Commit: "1a2b3c4d", ClusterIDs: []string{"9f3c2a1e"}}
+	require.NoError(t, store.Put("s3-controller", s3Record))
+	require.NoError(t, store.Put("sqs-controller", &Record{Image: "aws-controllers-k8s/sqs-controller:v0.0.1"}))
+
+	record, err = store.Get("s3-controller")
+	require.NoError(t, err)
+	assert.Equal(t, s3Record, record)
+	assert.True(t, record.LoadedInto("9f3c2a1e"))
+	assert.False(t, record.LoadedInto("4b7d0e52"))
+}
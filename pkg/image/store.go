@@ -0,0 +1,108 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
+)
+
+const (
+	storeFileName = "images.yaml"
+)
+
+// Record describes the last image built for a controller.
+type Record struct {
+	// Image is the image name, including its tag.
+	Image string `json:"image"`
+	// Commit is the controller HEAD commit when the image was built.
+	Commit string `json:"commit"`
+	// Dirty is true if the controller had uncommitted changes when the image
+	// was built.
+	Dirty bool `json:"dirty,omitempty"`
+	// ClusterIDs are the IDs of the kind clusters the image was loaded into.
+	// IDs are used instead of names because a recreated cluster keeps its name
+	// but loses the loaded images.
+	ClusterIDs []string `json:"clusterIDs,omitempty"`
+}
+
+// LoadedInto returns true if the image was loaded into the cluster with the
+// given ID.
+func (r *Record) LoadedInto(clusterID string) bool {
+	return util.InStrings(clusterID, r.ClusterIDs)
+}
+
+// Store records the last image built for each controller, so that other
+// commands can use it. Records are stored in <stateDirectory>/images.yaml.
+type Store struct {
+	path string
+}
+
+// NewStore returns a new Store persisting records in the given state
+// directory.
+func NewStore(stateDirectory string) *Store {
+	return &Store{
+		path: filepath.Join(stateDirectory, storeFileName),
+	}
+}
+
+func (s *Store) load() (map[string]*Record, error) {
+	records := map[string]*Record{}
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(b, &records)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Get returns the record of a controller, or nil if no image was built for
+// it.
+func (s *Store) Get(name string) (*Record, error) {
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return records[name], nil
+}
+
+// Put records the last image built for a controller.
+func (s *Store) Put(name string, record *Record) error {
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	records[name] = record
+
+	b, err := yaml.Marshal(records)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(s.path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, b, 0644)
+}
@@ -0,0 +1,165 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/tools"
+)
+
+// Commit is a commit included in a release.
+type Commit struct {
+	Hash    string
+	Subject string
+	// Type is the conventional commit type, e.g feat or fix. It is empty if
+	// the subject doesn't follow the conventional commits format.
+	Type string
+	// Scope is the optional conventional commit scope.
+	Scope string
+	// Description is the subject without the conventional commit prefix.
+	Description string
+	Breaking    bool
+}
+
+// conventionalPattern matches the conventional commit subjects:
+// <type>[(scope)][!]: <description>
+var conventionalPattern = regexp.MustCompile(`^([a-zA-Z]+)(\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// ParseCommit parses a commit subject.
+func ParseCommit(hash, subject string) Commit {
+	c := Commit{Hash: hash, Subject: subject, Description: subject}
+	matches := conventionalPattern.FindStringSubmatch(subject)
+	if matches == nil {
+		return c
+	}
+	c.Type = strings.ToLower(matches[1])
+	c.Scope = matches[3]
+	c.Breaking = matches[4] == "!"
+	c.Description = matches[5]
+	return c
+}
+
+// changelogSection is a group of commits in the changelog.
+type changelogSection struct {
+	Title string
+	Types []string
+}
+
+// changelogSections is the ordered list of the changelog sections. Breaking
+// commits are listed in the first section, and commits that don't match any
+// section in the last one.
+var changelogSections = []changelogSection{
+	{Title: "Breaking changes"},
+	{Title: "Features", Types: []string{"feat", "feature"}},
+	{Title: "Bug fixes", Types: []string{"fix", "bugfix"}},
+	{Title: "Performance improvements", Types: []string{"perf"}},
+	{Title: "Documentation", Types: []string{"docs", "doc"}},
+	{Title: "Maintenance", Types: []string{"chore", "refactor", "build", "ci", "test", "style"}},
+	{Title: "Other changes"},
+}
+
+var (
+	breakingSectionIndex = 0
+	otherSectionIndex    = len(changelogSections) - 1
+)
+
+// Changelog contains the commits of a release, grouped by conventional
+// commit types.
+type Changelog struct {
+	Version         string
+	PreviousVersion string
+	Commits         []Commit
+}
+
+// Markdown renders the changelog in markdown. Empty sections are omitted.
+func (c *Changelog) Markdown() string {
+	groups := make([][]Commit, len(changelogSections))
+	for _, commit := range c.Commits {
+		groups[sectionIndex(commit)] = append(groups[sectionIndex(commit)], commit)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", c.Version)
+	if c.PreviousVersion != "" {
+		fmt.Fprintf(&b, "\nChanges since %s.\n", c.PreviousVersion)
+	}
+	if len(c.Commits) == 0 {
+		b.WriteString("\nNo changes.\n")
+	}
+	for i, section := range changelogSections {
+		if len(groups[i]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Title)
+		for _, commit := range groups[i] {
+			b.WriteString("- ")
+			if commit.Scope != "" {
+				fmt.Fprintf(&b, "**%s**: ", commit.Scope)
+			}
+			fmt.Fprintf(&b, "%s (%s)\n", commit.Description, shortHash(commit.Hash))
+		}
+	}
+	return b.String()
+}
+
+func sectionIndex(commit Commit) int {
+	if commit.Breaking {
+		return breakingSectionIndex
+	}
+	for i, section := range changelogSections {
+		for _, t := range section.Types {
+			if commit.Type == t {
+				return i
+			}
+		}
+	}
+	return otherSectionIndex
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// logFormat separates the commit hashes and subjects with a tab.
+const logFormat = "--format=%H%x09%s"
+
+// Log returns the commits between a previous release tag and HEAD, newest
+// first, using the given git binary. If previousTag is empty, all the commits
+// reachable from HEAD are returned.
+func Log(ctx context.Context, gitBinary, repoPath, previousTag string) ([]Commit, error) {
+	revisions := "HEAD"
+	if previousTag != "" {
+		revisions = previousTag + "..HEAD"
+	}
+	b, err := tools.New(gitBinary).InDir(repoPath).Output(ctx, "log", "--no-merges", logFormat, revisions)
+	if err != nil {
+		return nil, err
+	}
+	commits := []Commit{}
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		commits = append(commits, ParseCommit(parts[0], parts[1]))
+	}
+	return commits, nil
+}
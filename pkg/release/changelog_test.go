@@ -0,0 +1,76 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommit(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    Commit
+	}{
+		{
+			subject: "feat: add bucket policies",
+			want:    Commit{Type: "feat", Description: "add bucket policies"},
+		},
+		{
+			subject: "Fix(api)!: rename the Bucket spec fields",
+			want:    Commit{Type: "fix", Scope: "api", Breaking: true, Description: "rename the Bucket spec fields"},
+		},
+		{
+			subject: "Update to ACK runtime `v0.15.2`",
+			want:    Commit{Description: "Update to ACK runtime `v0.15.2`"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			tt.want.Hash = "0123456789"
+			tt.want.Subject = tt.subject
+			assert.Equal(t, tt.want, ParseCommit("0123456789", tt.subject))
+		})
+	}
+}
+
+func TestChangelog_Markdown(t *testing.T) {
+	changelog := &Changelog{
+		Version:         "v0.1.0",
+		PreviousVersion: "v0.0.9",
+		Commits: []Commit{
+			ParseCommit("aaaaaaaaaa", "feat(bucket): support bucket policies"),
+			ParseCommit("bbbbbbbbbb", "fix: requeue on throttling errors"),
+			ParseCommit("cccccccccc", "Update to ACK runtime `v0.15.2`"),
+			ParseCommit("dddddddddd", "feat!: drop v1alpha1 API"),
+			ParseCommit("eeeeeeeeee", "chore: bump dependencies"),
+		},
+	}
+	assert.Equal(t, "## v0.1.0\n"+
+		"\nChanges since v0.0.9.\n"+
+		"\n### Breaking changes\n\n"+
+		"- drop v1alpha1 API (ddddddd)\n"+
+		"\n### Features\n\n"+
+		"- **bucket**: support bucket policies (aaaaaaa)\n"+
+		"\n### Bug fixes\n\n"+
+		"- requeue on throttling errors (bbbbbbb)\n"+
+		"\n### Maintenance\n\n"+
+		"- bump dependencies (eeeeeee)\n"+
+		"\n### Other changes\n\n"+
+		"- Update to ACK runtime `v0.15.2` (ccccccc)\n",
+		changelog.Markdown())
+
+	assert.Equal(t, "## v0.0.1\n\nNo changes.\n", (&Changelog{Version: "v0.0.1"}).Markdown())
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package release
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-release")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gitBinary, err := testutil.NewFakeBinary(dir, "git", `printf 'aaaaaaaaaa\tfeat: support bucket policies\n'
+printf 'bbbbbbbbbb\tfix: requeue on errors\n'`)
+	require.NoError(t, err)
+
+	commits, err := Log(context.Background(), gitBinary, dir, "v0.0.9")
+	require.NoError(t, err)
+	assert.Equal(t, []Commit{
+		ParseCommit("aaaaaaaaaa", "feat: support bucket policies"),
+		ParseCommit("bbbbbbbbbb", "fix: requeue on errors"),
+	}, commits)
+
+	_, err = Log(context.Background(), gitBinary, dir, "")
+	require.NoError(t, err)
+
+	calls, err := testutil.FakeBinaryCalls(dir, "git")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"log --no-merges --format=%H%x09%s v0.0.9..HEAD",
+		"log --no-merges --format=%H%x09%s HEAD",
+	}, calls)
+}
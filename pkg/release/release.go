@@ -0,0 +1,130 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package release prepares the releases of service controllers: it bumps the
+// versions of their Helm chart and kustomize images, and generates their
+// changelog.
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+const (
+	// ChartFile is the Helm chart of the controllers, relative to the
+	// controller repositories.
+	ChartFile = "helm/Chart.yaml"
+	// KustomizationFile is the kustomization of the controller deployment,
+	// relative to the controller repositories.
+	KustomizationFile = "config/controller/kustomization.yaml"
+)
+
+var (
+	ErrVersionExists     error = errors.New("release tag already exists")
+	ErrVersionNotGreater error = errors.New("release version must be greater than the latest release")
+	ErrFieldNotFound     error = errors.New("version field not found")
+)
+
+// ValidateVersion checks that a new release version is a valid semantic
+// version greater than all the existing release tags, and returns the latest
+// release tag, or an empty string for a first release.
+func ValidateVersion(version string, tags []string) (string, error) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag == version {
+			return "", fmt.Errorf("%w: %s", ErrVersionExists, tag)
+		}
+	}
+	latest := semver.Latest(tags)
+	if latest != "" && !semver.MustParse(latest).LessThan(v) {
+		return "", fmt.Errorf("%w: %s is not greater than %s", ErrVersionNotGreater, version, latest)
+	}
+	return latest, nil
+}
+
+// fieldPatterns lists the version fields updated in each release file.
+var fieldPatterns = map[string][]*regexp.Regexp{
+	ChartFile: {
+		fieldPattern("", "version"),
+		fieldPattern("", "appVersion"),
+	},
+	KustomizationFile: {
+		// image tags are nested in the images list
+		fieldPattern(`[ \t]*-?[ \t]*`, "newTag"),
+	},
+}
+
+// fieldPattern matches a YAML field, capturing its key, its quote and its
+// value. indent is a pattern matching the indentation of the field.
+func fieldPattern(indent, key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^(` + indent + key + `:[ \t]*)(["']?)([^"'\s#]*)(["']?)`)
+}
+
+// UpdateFiles sets the release version in the Helm chart and in the
+// kustomization of a controller repository, and returns the updated files.
+// Existing values keep their style: quoted or not, with or without the "v"
+// prefix.
+func UpdateFiles(repoPath, version string) ([]string, error) {
+	updated := []string{}
+	for _, file := range []string{ChartFile, KustomizationFile} {
+		path := filepath.Join(repoPath, file)
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		content := string(b)
+		for _, pattern := range fieldPatterns[file] {
+			if !pattern.MatchString(content) {
+				return nil, fmt.Errorf("%w: %s in %s", ErrFieldNotFound, pattern.String(), file)
+			}
+			content = pattern.ReplaceAllStringFunc(content, func(field string) string {
+				groups := pattern.FindStringSubmatch(field)
+				return groups[1] + groups[2] + styledVersion(groups[3], version) + groups[4]
+			})
+		}
+		if content == string(b) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(path, []byte(content), info.Mode())
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, file)
+	}
+	return updated, nil
+}
+
+// styledVersion returns the version with a "v" prefix only if the previous
+// value had one.
+func styledVersion(previous, version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if strings.HasPrefix(previous, "v") {
+		return "v" + version
+	}
+	return version
+}
@@ -0,0 +1,136 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package release
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+func TestValidateVersion(t *testing.T) {
+	tags := []string{"v0.0.8", "v0.0.9", "v0.1.0-rc.1", "stable"}
+	tests := []struct {
+		name         string
+		version      string
+		tags         []string
+		wantPrevious string
+		wantErr      error
+	}{
+		{name: "first release", version: "v0.0.1", wantPrevious: ""},
+		{name: "patch release", version: "v0.0.10", tags: tags, wantPrevious: "v0.0.9"},
+		{name: "release after a pre-release", version: "v0.1.0", tags: tags, wantPrevious: "v0.0.9"},
+		{name: "existing tag", version: "v0.0.9", tags: tags, wantErr: ErrVersionExists},
+		{name: "older version", version: "v0.0.7", tags: tags, wantErr: ErrVersionNotGreater},
+		{name: "invalid version", version: "next", tags: tags, wantErr: semver.ErrInvalidVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, err := ValidateVersion(tt.version, tt.tags)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPrevious, previous)
+		})
+	}
+}
+
+const (
+	chartContent = `apiVersion: v1
+name: s3-chart
+description: A Helm chart for the ACK service controller
+version: v0.0.9
+appVersion: "v0.0.9"
+dependencies:
+  - name: common
+    version: 1.2.3
+`
+	kustomizationContent = `resources:
+- deployment.yaml
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: controller
+  newName: public.ecr.aws/aws-controllers-k8s/s3-controller
+  newTag: 0.0.9 # release tag
+`
+)
+
+func writeReleaseFiles(t *testing.T, dir, chart, kustomization string) {
+	for path, content := range map[string]string{ChartFile: chart, KustomizationFile: kustomization} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), os.ModePerm))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+	}
+}
+
+func TestUpdateFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-release")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeReleaseFiles(t, dir, chartContent, kustomizationContent)
+
+	updated, err := UpdateFiles(dir, "v0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{ChartFile, KustomizationFile}, updated)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, ChartFile))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+name: s3-chart
+description: A Helm chart for the ACK service controller
+version: v0.1.0
+appVersion: "v0.1.0"
+dependencies:
+  - name: common
+    version: 1.2.3
+`, string(b))
+	b, err = ioutil.ReadFile(filepath.Join(dir, KustomizationFile))
+	require.NoError(t, err)
+	assert.Equal(t, `resources:
+- deployment.yaml
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+- name: controller
+  newName: public.ecr.aws/aws-controllers-k8s/s3-controller
+  newTag: 0.1.0 # release tag
+`, string(b))
+
+	// files already at the release version are not updated
+	updated, err = UpdateFiles(dir, "v0.1.0")
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestUpdateFiles_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-release")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = UpdateFiles(dir, "v0.1.0")
+	assert.True(t, os.IsNotExist(err))
+
+	writeReleaseFiles(t, dir, chartContent, "resources:\n- deployment.yaml\n")
+	_, err = UpdateFiles(dir, "v0.1.0")
+	assert.True(t, errors.Is(err, ErrFieldNotFound))
+}
@@ -0,0 +1,281 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/github"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
+)
+
+var (
+	// scanSkippedDirectories are the directories that are never walked when
+	// scanning for local repositories.
+	scanSkippedDirectories = []string{"vendor", "node_modules"}
+)
+
+// Candidate is a local git repository that was identified as an ACK
+// repository, and that can be adopted by the Manager.
+type Candidate struct {
+	// Name of the ACK upstream repo
+	Name string
+	// Repository Type
+	Type RepositoryType
+	// Local path of the repository
+	Path string
+	// Err is the error preventing the repository from being identified, in
+	// which case it can't be adopted.
+	Err error
+}
+
+// configName returns the name used to reference the candidate in the
+// configuration. Controllers are referenced by their service name.
+func (c *Candidate) configName() string {
+	if c.Type == RepositoryTypeController {
+		return strings.TrimSuffix(c.Name, "-controller")
+	}
+	return c.Name
+}
+
+// Scan walks a directory tree and returns the ACK repositories it contains.
+// Repositories are identified by their remote URLs, which should either point
+// to the ACK organisation or to a fork owned by the configured Github user.
+// Repositories that can't be identified are returned with their error, and
+// don't stop the scan.
+func (m *Manager) Scan(root string) ([]*Candidate, error) {
+	candidates := []*Candidate{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(info.Name(), ".") ||
+			util.InStrings(info.Name(), scanSkippedDirectories)) {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+
+		candidate, err := m.identify(path)
+		if err != nil {
+			candidate = &Candidate{Path: path, Err: err}
+		}
+		if candidate != nil {
+			candidates = append(candidates, candidate)
+		}
+		// nested repositories are not supported
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// identify opens a local git repository and tries to find the ACK repository
+// it is a clone of. It returns nil if the repository is not an ACK repository.
+func (m *Manager) identify(path string) (*Candidate, error) {
+	gitRepo, err := m.git.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open repository %s: %v", path, err)
+	}
+	remotes, err := util.GetRepositoryRemotes(gitRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	// upstream URLs are the most reliable source of truth, fork names are only
+	// used when no remote points to the upstream repository.
+	upstreamName, forkName := "", ""
+	for _, urls := range remotes {
+		for _, url := range urls {
+			owner, repoName, err := util.ParseGithubURL(url)
+			if err != nil {
+				continue
+			}
+			if strings.EqualFold(owner, github.ACKOrg) {
+				upstreamName = repoName
+			} else if m.cfg.Github.Username != "" && strings.EqualFold(owner, m.cfg.Github.Username) {
+				forkName = strings.TrimPrefix(repoName, m.cfg.Github.ForkPrefix)
+			}
+		}
+	}
+	name := upstreamName
+	if name == "" {
+		name = forkName
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	candidate := &Candidate{
+		Name: name,
+		Path: path,
+	}
+	switch {
+	case strings.HasSuffix(name, "-controller"):
+		candidate.Type = RepositoryTypeController
+	case config.FindRepository(m.cfg.Repositories.Core, name) != nil,
+		config.FindRepository(config.DefaultConfig.Repositories.Core, name) != nil:
+		candidate.Type = RepositoryTypeCore
+	case config.FindRepository(m.cfg.Repositories.Tooling, name) != nil:
+		candidate.Type = RepositoryTypeTooling
+	default:
+		return nil, nil
+	}
+	return candidate, nil
+}
+
+// Adopt registers a candidate repository in the configuration. If move is true
+// the repository is moved into the root directory, otherwise its location is
+// recorded in the configuration. The configuration is only modified once the
+// repository is in its final location. The repository remotes are then
+// rewritten to follow the origin (fork) and upstream (ACK) convention.
+func (m *Manager) Adopt(c *Candidate, move bool) (*Repository, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	path, err := filepath.Abs(c.Path)
+	if err != nil {
+		return nil, err
+	}
+	managedPath := filepath.Join(m.cfg.RootDirectory, c.Name)
+
+	location := ""
+	if path != managedPath {
+		if move {
+			if _, err := os.Stat(managedPath); err == nil {
+				return nil, fmt.Errorf("cannot move %s to %s: %w", path, managedPath, ErrRepositoryAlreadyExist)
+			}
+			err = os.MkdirAll(m.cfg.RootDirectory, os.ModePerm)
+			if err != nil {
+				return nil, err
+			}
+			err = os.Rename(path, managedPath)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			location = path
+		}
+	}
+
+	name := c.configName()
+	repoConfig, err := m.adoptedRepositoryConfig(name, c.Type)
+	if err != nil {
+		return nil, err
+	}
+	repoConfig.Path = location
+
+	// force the repository to be reloaded from its new location
+	delete(m.repoCache, name)
+	repo, err := m.AddRepository(name, c.Type)
+	if err != nil {
+		return nil, err
+	}
+	if repo.gitRepo == nil {
+		return nil, fmt.Errorf("cannot open adopted repository %s: %v", repo.FullPath, ErrRepositoryDoesntExist)
+	}
+
+	err = m.rewriteRemotes(repo)
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// adoptedRepositoryConfig returns the configuration of an adopted repository,
+// adding it to the configuration if needed.
+func (m *Manager) adoptedRepositoryConfig(name string, t RepositoryType) (*config.RepositoryConfig, error) {
+	repos, err := m.configuredRepositories(t)
+	if err != nil {
+		return nil, err
+	}
+	if repoConfig := config.FindRepository(*repos, name); repoConfig != nil {
+		return repoConfig, nil
+	}
+	*repos = append(*repos, config.RepositoryConfig{Name: name})
+	return &(*repos)[len(*repos)-1], nil
+}
+
+// rewriteRemotes rewrites the remotes of a repository to follow the convention
+// expected by EnsureRemotes: origin points to the user fork and upstream points
+// to the ACK repository. Any other remote pointing to one of these repositories
+// is removed, and the branches tracking it are updated accordingly.
+func (m *Manager) rewriteRemotes(repo *Repository) error {
+	cfg, err := repo.gitRepo.Storer.Config()
+	if err != nil {
+		return err
+	}
+
+	// maps the old remote names to the new ones
+	renames := map[string]string{}
+	for name, remote := range cfg.Remotes {
+		for _, url := range remote.URLs {
+			owner, repoName, err := util.ParseGithubURL(url)
+			if err != nil {
+				continue
+			}
+			if strings.EqualFold(owner, repo.UpstreamOwner) && repoName == repo.Name {
+				renames[name] = upstreamRemoteName
+				break
+			}
+			if strings.EqualFold(owner, m.cfg.Github.Username) && repoName == repo.ExpectedForkName {
+				renames[name] = originRemoteName
+				break
+			}
+		}
+	}
+	for oldName := range renames {
+		delete(cfg.Remotes, oldName)
+	}
+	// origin might still point to an unrelated repository, in which case it
+	// is renamed to keep it around.
+	for _, name := range []string{originRemoteName, upstreamRemoteName} {
+		if remote, ok := cfg.Remotes[name]; ok {
+			previousName := "previous-" + name
+			remote.Name = previousName
+			remote.Fetch = nil
+			cfg.Remotes[previousName] = remote
+			delete(cfg.Remotes, name)
+			renames[name] = previousName
+		}
+	}
+
+	cfg.Remotes[originRemoteName] = &gitconfig.RemoteConfig{
+		Name: originRemoteName,
+		URLs: []string{m.urlBuilder(m.cfg.Github.Username, repo.ExpectedForkName)},
+	}
+	cfg.Remotes[upstreamRemoteName] = &gitconfig.RemoteConfig{
+		Name: upstreamRemoteName,
+		URLs: []string{m.urlBuilder(repo.UpstreamOwner, repo.Name)},
+	}
+
+	for _, branch := range cfg.Branches {
+		if newName, ok := renames[branch.Remote]; ok {
+			branch.Remote = newName
+		}
+	}
+	return repo.gitRepo.Storer.SetConfig(cfg)
+}
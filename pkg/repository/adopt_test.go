@@ -0,0 +1,172 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package repository
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
+	ackdevgit "github.com/aws-controllers-k8s/dev-tools/pkg/git"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+	"github.com/aws-controllers-k8s/dev-tools/pkg/util"
+)
+
+// newGopathLayout creates a directory tree looking like an old GOPATH
+// containing ACK and non ACK repositories.
+func newGopathLayout(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ackdev-adopt")
+	require.NoError(t, err)
+
+	repos := map[string]map[string]string{
+		"src/github.com/aws-controllers-k8s/runtime": {
+			"origin": "https://github.com/aws-controllers-k8s/runtime.git",
+		},
+		"src/github.com/aws-controllers-k8s/s3-controller": {
+			"origin": "git@github.com:ack-bot/ack-s3-controller.git",
+			"ack":    "git@github.com:aws-controllers-k8s/s3-controller.git",
+		},
+		// the fork name doesn't follow the conventions, the upstream name is used
+		"src/github.com/ack-bot/ecr": {
+			"origin":   "https://github.com/ack-bot/ack-ecr-controller-old.git",
+			"upstream": "https://github.com/aws-controllers-k8s/ecr-controller.git",
+		},
+		"src/github.com/ack-bot/ack-sqs-controller": {
+			"origin": "https://github.com/ack-bot/ack-sqs-controller.git",
+		},
+		"src/github.com/kubernetes/kubernetes": {
+			"origin": "https://github.com/kubernetes/kubernetes.git",
+		},
+	}
+	for path, remotes := range repos {
+		_, err := testutil.NewGitRepository(filepath.Join(dir, path), remotes)
+		require.NoError(t, err)
+	}
+	// a broken repository, which can't be opened
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src/github.com/ack-bot/broken/.git"), os.ModePerm))
+	return dir
+}
+
+func newAdoptManager(rootDir string) *Manager {
+	cfg := testutil.NewConfig()
+	cfg.RootDirectory = rootDir
+	return &Manager{
+		cfg:        cfg,
+		git:        ackdevgit.New(),
+		urlBuilder: httpsRemoteURL,
+		repoCache:  make(map[string]*Repository),
+	}
+}
+
+func TestManager_Scan(t *testing.T) {
+	gopath := newGopathLayout(t)
+	defer os.RemoveAll(gopath)
+
+	m := newAdoptManager(filepath.Join(gopath, "ack"))
+	candidates, err := m.Scan(gopath)
+	require.NoError(t, err)
+
+	found := map[string]RepositoryType{}
+	for _, c := range candidates {
+		if c.Err != nil {
+			// the broken repository doesn't stop the scan
+			assert.Equal(t, filepath.Join(gopath, "src/github.com/ack-bot/broken"), c.Path)
+			continue
+		}
+		found[c.Name] = c.Type
+	}
+	assert.Equal(t, map[string]RepositoryType{
+		"runtime":        RepositoryTypeCore,
+		"s3-controller":  RepositoryTypeController,
+		"ecr-controller": RepositoryTypeController,
+		"sqs-controller": RepositoryTypeController,
+	}, found)
+}
+
+func TestManager_Adopt(t *testing.T) {
+	gopath := newGopathLayout(t)
+	defer os.RemoveAll(gopath)
+
+	rootDir := filepath.Join(gopath, "ack")
+	m := newAdoptManager(rootDir)
+	candidates, err := m.Scan(gopath)
+	require.NoError(t, err)
+
+	for _, c := range candidates {
+		if c.Err != nil {
+			_, err := m.Adopt(c, false)
+			assert.Equal(t, c.Err, err)
+			continue
+		}
+		// only move the s3 controller, keep the others in place.
+		move := c.Name == "s3-controller"
+		_, err := m.Adopt(c, move)
+		require.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []config.RepositoryConfig{
+		{Name: "s3"},
+		{Name: "ecr", Path: filepath.Join(gopath, "src/github.com/ack-bot/ecr")},
+		{Name: "sqs", Path: filepath.Join(gopath, "src/github.com/ack-bot/ack-sqs-controller")},
+	}, m.cfg.Repositories.Services)
+	assert.Equal(t,
+		filepath.Join(gopath, "src/github.com/aws-controllers-k8s/runtime"),
+		config.FindRepository(m.cfg.Repositories.Core, "runtime").Path,
+	)
+
+	// s3-controller was moved into the root directory and its remotes rewritten.
+	gitRepo, err := git.PlainOpen(filepath.Join(rootDir, "s3-controller"))
+	require.NoError(t, err)
+	remotes, err := util.GetRepositoryRemotes(gitRepo)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"origin":   {"https://github.com/ack-bot/ack-s3-controller.git"},
+		"upstream": {"https://github.com/aws-controllers-k8s/s3-controller.git"},
+	}, remotes)
+
+	// runtime origin was pointing to the ACK repository, it is now upstream.
+	repo, err := m.getRepository("runtime")
+	require.NoError(t, err)
+	remotes, err = util.GetRepositoryRemotes(repo.gitRepo)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"origin":   {"https://github.com/ack-bot/ack-runtime.git"},
+		"upstream": {"https://github.com/aws-controllers-k8s/runtime.git"},
+	}, remotes)
+}
+
+func TestManager_Adopt_MoveFailed(t *testing.T) {
+	gopath := newGopathLayout(t)
+	defer os.RemoveAll(gopath)
+
+	rootDir := filepath.Join(gopath, "ack")
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "sqs-controller"), os.ModePerm))
+	m := newAdoptManager(rootDir)
+	_, err := m.Adopt(&Candidate{
+		Name: "sqs-controller",
+		Type: RepositoryTypeController,
+		Path: filepath.Join(gopath, "src/github.com/ack-bot/ack-sqs-controller"),
+	}, true)
+	assert.True(t, errors.Is(err, ErrRepositoryAlreadyExist), err)
+
+	// the configuration is left untouched
+	assert.Nil(t, config.FindRepository(m.cfg.Repositories.Services, "sqs"))
+}
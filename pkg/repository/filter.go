@@ -44,7 +44,11 @@ func BuildFilters(expression string) ([]Filter, error) {
 		value := filterArgs[1]
 		switch key {
 		case "type":
-			filters = append(filters, TypeFilter(value))
+			repoType, err := GetRepositoryTypeFromString(value)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, TypeFilter(repoType))
 		case "name":
 			filters = append(filters, NameFilter(value))
 		case "branch":
@@ -78,11 +82,10 @@ func NamePrefixFilter(namePrefix string) Filter {
 	}
 }
 
-// TypeFilter filters a repository by a name prefix
-// The only two possible types are 'controller' and 'core'
-func TypeFilter(t string) Filter {
+// TypeFilter filters all repositories whose type matches the given type.
+func TypeFilter(t RepositoryType) Filter {
 	return func(r *Repository) bool {
-		return r.Type == GetRepositoryTypeFromString(t)
+		return r.Type == t
 	}
 }
 
@@ -15,6 +15,7 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,6 +63,18 @@ func TestBuildFilters(t *testing.T) {
 			wantLenFilter: 1,
 			wantErr:       false,
 		},
+		{
+			name:          "correct expression - tooling type",
+			args:          args{expression: "type=tooling"},
+			wantLenFilter: 1,
+			wantErr:       false,
+		},
+		{
+			name:          "unsupported repository type",
+			args:          args{expression: "name=runtime type=library"},
+			wantLenFilter: 0,
+			wantErr:       true,
+		},
 		{
 			name:          "correct expression - all filters",
 			args:          args{expression: "type=core branch=main name=runtime"},
@@ -112,19 +125,57 @@ func TestNamePrefixFilter(t *testing.T) {
 }
 
 func TestTypeFilter(t *testing.T) {
-	repoTypeFilter := TypeFilter(RepositoryTypeCore.String())
 	runtimeRepo := &Repository{
 		Name: "runtime",
 		Type: RepositoryTypeCore,
 	}
+	testInfraRepo := &Repository{
+		Name: "test-infra",
+		Type: RepositoryTypeTooling,
+	}
 	sqsRepo := &Repository{
 		Name: "sqs",
 		Type: RepositoryTypeController,
 	}
+
+	repoTypeFilter := TypeFilter(RepositoryTypeCore)
 	assert.True(t, repoTypeFilter(runtimeRepo))
+	assert.False(t, repoTypeFilter(testInfraRepo))
+	assert.False(t, repoTypeFilter(sqsRepo))
+
+	repoTypeFilter = TypeFilter(RepositoryTypeTooling)
+	assert.False(t, repoTypeFilter(runtimeRepo))
+	assert.True(t, repoTypeFilter(testInfraRepo))
 	assert.False(t, repoTypeFilter(sqsRepo))
 }
 
+func TestGetRepositoryTypeFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    RepositoryType
+		wantErr bool
+	}{
+		{name: "core", s: "core", want: RepositoryTypeCore},
+		{name: "tooling", s: "tooling", want: RepositoryTypeTooling},
+		{name: "controller", s: "Controller", want: RepositoryTypeController},
+		{name: "unsupported", s: "library", want: RepositoryTypeUnknown, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetRepositoryTypeFromString(tt.s)
+			if (err != nil) != tt.wantErr {
+				assert.Fail(t, fmt.Sprintf("GetRepositoryTypeFromString() error = %v, wantErr %v", err, tt.wantErr))
+			}
+			assert.Equal(t, tt.want, got)
+			if !tt.wantErr {
+				// String and GetRepositoryTypeFromString should be symmetric
+				assert.Equal(t, strings.ToLower(tt.s), got.String())
+			}
+		})
+	}
+}
+
 func TestBranchFilter(t *testing.T) {
 	branchFilter := BranchFilter("main")
 	runtimeRepo := &Repository{
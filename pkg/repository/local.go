@@ -0,0 +1,291 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/semver"
+)
+
+const (
+	stashReferenceName = plumbing.ReferenceName("refs/stash")
+)
+
+var (
+	ErrUncommittedChanges error = errors.New("repository has uncommitted changes")
+	ErrStashedChanges     error = errors.New("repository has stashed changes")
+	ErrUnpushedBranches   error = errors.New("repository has unpushed branches")
+)
+
+// Cloned returns true if the repository exists locally.
+func (r *Repository) Cloned() bool {
+	return r.gitRepo != nil
+}
+
+// HeadCommit returns the hash of the commit pointed by HEAD.
+func (r *Repository) HeadCommit() (string, error) {
+	if !r.Cloned() {
+		return "", ErrRepositoryDoesntExist
+	}
+	head, err := r.gitRepo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// IsDirty returns true if the repository has uncommitted changes, including
+// untracked files.
+func (r *Repository) IsDirty() (bool, error) {
+	if !r.Cloned() {
+		return false, ErrRepositoryDoesntExist
+	}
+	worktree, err := r.gitRepo.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false, err
+	}
+	return !status.IsClean(), nil
+}
+
+// ChangesHash returns a hash of the uncommitted changes of the repository,
+// including untracked files, or an empty string if it has none. The hash
+// covers the status and the content of every changed file, so it differs for
+// different uncommitted trees on top of the same commit.
+func (r *Repository) ChangesHash() (string, error) {
+	if !r.Cloned() {
+		return "", ErrRepositoryDoesntExist
+	}
+	worktree, err := r.gitRepo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return "", err
+	}
+	if status.IsClean() {
+		return "", nil
+	}
+
+	paths := make([]string, 0, len(status))
+	for path := range status {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fileStatus := status[path]
+		fmt.Fprintf(h, "%s %c%c\n", path, fileStatus.Staging, fileStatus.Worktree)
+		content, err := ioutil.ReadFile(filepath.Join(r.FullPath, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%d\n", len(content))
+		h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// BranchExists returns true if the repository has a local branch with the
+// given name.
+func (r *Repository) BranchExists(name string) (bool, error) {
+	if !r.Cloned() {
+		return false, ErrRepositoryDoesntExist
+	}
+	_, err := r.gitRepo.Reference(plumbing.NewBranchReferenceName(name), false)
+	if err == plumbing.ErrReferenceNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Tags returns the names of the repository tags.
+func (r *Repository) Tags() ([]string, error) {
+	if !r.Cloned() {
+		return nil, ErrRepositoryDoesntExist
+	}
+	iter, err := r.gitRepo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// LatestReleaseTag returns the greatest semantic version tag of the
+// repository, ignoring pre-releases, or an empty string if the repository
+// wasn't released yet.
+func (r *Repository) LatestReleaseTag() (string, error) {
+	tags, err := r.Tags()
+	if err != nil {
+		return "", err
+	}
+	return semver.Latest(tags), nil
+}
+
+// CheckSafeToDelete returns an error if deleting the local repository would
+// lose some work: uncommitted changes (including untracked files), stashes or
+// branches that were not pushed to origin.
+func (r *Repository) CheckSafeToDelete() error {
+	return r.checkSafeToDelete(originRemoteName)
+}
+
+// CheckSafeToDeleteWithFork is like CheckSafeToDelete, for a repository whose
+// fork is deleted too: origin is the fork, so branches must have been pushed
+// to upstream.
+func (r *Repository) CheckSafeToDeleteWithFork() error {
+	return r.checkSafeToDelete(upstreamRemoteName)
+}
+
+func (r *Repository) checkSafeToDelete(remote string) error {
+	if !r.Cloned() {
+		return nil
+	}
+
+	dirty, err := r.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrUncommittedChanges
+	}
+
+	_, err = r.gitRepo.Reference(stashReferenceName, false)
+	if err == nil {
+		return ErrStashedChanges
+	}
+	if err != plumbing.ErrReferenceNotFound {
+		return err
+	}
+
+	branches, err := r.unpushedBranches(remote)
+	if err != nil {
+		return err
+	}
+	if len(branches) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnpushedBranches, strings.Join(branches, ", "))
+	}
+	return nil
+}
+
+// UnpushedBranches returns the local branches containing commits that are not
+// reachable from any of the origin remote branches.
+func (r *Repository) UnpushedBranches() ([]string, error) {
+	return r.unpushedBranches(originRemoteName)
+}
+
+// unpushedBranches returns the local branches containing commits that are not
+// reachable from any of the given remote branches.
+func (r *Repository) unpushedBranches(remote string) ([]string, error) {
+	if !r.Cloned() {
+		return nil, nil
+	}
+
+	// collect the commits pointed by the remote branches
+	originHeads := map[plumbing.Hash]bool{}
+	refs, err := r.gitRepo.References()
+	if err != nil {
+		return nil, err
+	}
+	originPrefix := fmt.Sprintf("refs/remotes/%s/", remote)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), originPrefix) {
+			originHeads[ref.Hash()] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the set of commits reachable from the remote is only computed if one of
+	// the branches doesn't point directly to an origin branch.
+	var reachable map[plumbing.Hash]bool
+
+	unpushed := []string{}
+	branches, err := r.gitRepo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		if originHeads[ref.Hash()] {
+			return nil
+		}
+		if reachable == nil {
+			reachable, err = r.reachableCommits(originHeads)
+			if err != nil {
+				return err
+			}
+		}
+		if !reachable[ref.Hash()] {
+			unpushed = append(unpushed, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unpushed, nil
+}
+
+// reachableCommits returns the set of commits reachable from the given heads.
+func (r *Repository) reachableCommits(heads map[plumbing.Hash]bool) (map[plumbing.Hash]bool, error) {
+	reachable := map[plumbing.Hash]bool{}
+	for head := range heads {
+		commit, err := r.gitRepo.CommitObject(head)
+		if err != nil {
+			return nil, err
+		}
+		// commits already visited from another head are skipped
+		err = object.NewCommitPreorderIter(commit, reachable, nil).ForEach(func(c *object.Commit) error {
+			reachable[c.Hash] = true
+			return nil
+		})
+		if err != nil && err != storer.ErrStop {
+			return nil, err
+		}
+	}
+	return reachable, nil
+}
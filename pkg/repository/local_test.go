@@ -0,0 +1,218 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package repository
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// newPushedRepository creates an on-disk repository whose master branch is
+// up to date with origin.
+func newPushedRepository(t *testing.T) (*Repository, func()) {
+	dir, err := ioutil.TempDir("", "ackdev-local")
+	require.NoError(t, err)
+
+	gitRepo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	commitFile(t, gitRepo, "README.md")
+
+	head, err := gitRepo.Head()
+	require.NoError(t, err)
+	err = gitRepo.Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewRemoteReferenceName(originRemoteName, "master"), head.Hash(),
+	))
+	require.NoError(t, err)
+
+	repo := &Repository{Name: "s3-controller", FullPath: dir, gitRepo: gitRepo}
+	return repo, func() { os.RemoveAll(dir) }
+}
+
+func commitFile(t *testing.T, gitRepo *git.Repository, name string) plumbing.Hash {
+	w, err := gitRepo.Worktree()
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(w.Filesystem.Root(), name), []byte(name), 0644)
+	require.NoError(t, err)
+	_, err = w.Add(name)
+	require.NoError(t, err)
+	hash, err := w.Commit("add "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "ackdev", Email: "ackdev@example.com"},
+	})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestRepository_CheckSafeToDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, repo *Repository)
+		wantErr error
+	}{
+		{
+			name:    "clean and pushed repository",
+			setup:   func(t *testing.T, repo *Repository) {},
+			wantErr: nil,
+		},
+		{
+			name: "local branch merged in origin",
+			setup: func(t *testing.T, repo *Repository) {
+				head, err := repo.gitRepo.Head()
+				require.NoError(t, err)
+				err = repo.gitRepo.Storer.SetReference(plumbing.NewHashReference(
+					plumbing.NewBranchReferenceName("merged"), head.Hash(),
+				))
+				require.NoError(t, err)
+				commitFile(t, repo.gitRepo, "CHANGELOG.md")
+				head, err = repo.gitRepo.Head()
+				require.NoError(t, err)
+				err = repo.gitRepo.Storer.SetReference(plumbing.NewHashReference(
+					plumbing.NewRemoteReferenceName(originRemoteName, "master"), head.Hash(),
+				))
+				require.NoError(t, err)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "untracked file",
+			setup: func(t *testing.T, repo *Repository) {
+				err := ioutil.WriteFile(filepath.Join(repo.FullPath, "notes.txt"), []byte("wip"), 0644)
+				require.NoError(t, err)
+			},
+			wantErr: ErrUncommittedChanges,
+		},
+		{
+			name: "stashed changes",
+			setup: func(t *testing.T, repo *Repository) {
+				head, err := repo.gitRepo.Head()
+				require.NoError(t, err)
+				err = repo.gitRepo.Storer.SetReference(plumbing.NewHashReference(stashReferenceName, head.Hash()))
+				require.NoError(t, err)
+			},
+			wantErr: ErrStashedChanges,
+		},
+		{
+			name: "unpushed commit",
+			setup: func(t *testing.T, repo *Repository) {
+				commitFile(t, repo.gitRepo, "CHANGELOG.md")
+			},
+			wantErr: ErrUnpushedBranches,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, cleanup := newPushedRepository(t)
+			defer cleanup()
+
+			tt.setup(t, repo)
+			err := repo.CheckSafeToDelete()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRepository_CheckSafeToDelete_NotCloned(t *testing.T) {
+	repo := &Repository{Name: "s3-controller"}
+	assert.NoError(t, repo.CheckSafeToDelete())
+}
+
+func TestRepository_LatestReleaseTag(t *testing.T) {
+	repo, cleanup := newPushedRepository(t)
+	defer cleanup()
+
+	latest, err := repo.LatestReleaseTag()
+	require.NoError(t, err)
+	assert.Equal(t, "", latest)
+
+	head, err := repo.gitRepo.Head()
+	require.NoError(t, err)
+	for _, tag := range []string{"v0.9.0", "v0.10.0", "v0.11.0-rc.1", "stable"} {
+		_, err = repo.gitRepo.CreateTag(tag, head.Hash(), nil)
+		require.NoError(t, err)
+	}
+
+	tags, err := repo.Tags()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"v0.9.0", "v0.10.0", "v0.11.0-rc.1", "stable"}, tags)
+
+	latest, err = repo.LatestReleaseTag()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.10.0", latest)
+
+	_, err = (&Repository{Name: "sqs-controller"}).Tags()
+	assert.Equal(t, ErrRepositoryDoesntExist, err)
+}
+
+func TestRepository_CheckSafeToDeleteWithFork(t *testing.T) {
+	repo, cleanup := newPushedRepository(t)
+	defer cleanup()
+
+	// master is only pushed to origin, which is the fork
+	err := repo.CheckSafeToDeleteWithFork()
+	assert.True(t, errors.Is(err, ErrUnpushedBranches), "expected %v, got %v", ErrUnpushedBranches, err)
+
+	head, err := repo.gitRepo.Head()
+	require.NoError(t, err)
+	err = repo.gitRepo.Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewRemoteReferenceName(upstreamRemoteName, "main"), head.Hash(),
+	))
+	require.NoError(t, err)
+	assert.NoError(t, repo.CheckSafeToDeleteWithFork())
+}
+
+func TestRepository_ChangesHash(t *testing.T) {
+	repo, cleanup := newPushedRepository(t)
+	defer cleanup()
+
+	hash, err := repo.ChangesHash()
+	require.NoError(t, err)
+	assert.Equal(t, "", hash)
+
+	wip := filepath.Join(repo.FullPath, "wip.go")
+	require.NoError(t, ioutil.WriteFile(wip, []byte("package main"), 0644))
+	first, err := repo.ChangesHash()
+	require.NoError(t, err)
+	assert.NotEmpty(t, first)
+
+	// the hash is deterministic
+	again, err := repo.ChangesHash()
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// and changes with the content of the files
+	require.NoError(t, ioutil.WriteFile(wip, []byte("package main\n\nfunc main() {}"), 0644))
+	second, err := repo.ChangesHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	require.NoError(t, os.Remove(wip))
+	require.NoError(t, os.Remove(filepath.Join(repo.FullPath, "README.md")))
+	deleted, err := repo.ChangesHash()
+	require.NoError(t, err)
+	assert.NotEmpty(t, deleted)
+	assert.NotEqual(t, first, deleted)
+	assert.NotEqual(t, second, deleted)
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -35,6 +36,7 @@ import (
 const (
 	originRemoteName   = "origin"
 	upstreamRemoteName = "upstream"
+	defaultBranch      = "main"
 )
 
 var (
@@ -59,9 +61,9 @@ func NewManager(cfg *config.Config) (*Manager, error) {
 			ackdevgit.WithGithubCredentials(cfg.Github.Username, cfg.Github.Token),
 		)
 	} else {
-		// TODO(hilalymh) set ssh.Signer here.. figure out how to deal with encrypted
-		// keys properly...
-		gitOpts = append(gitOpts, ackdevgit.WithSSHSigner(nil))
+		// The signer is loaded lazily, so that encrypted keys only prompt for
+		// a passphrase when a git operation actually needs them.
+		gitOpts = append(gitOpts, ackdevgit.WithSSHKeyPath(cfg.Git.SSHKeyPath))
 		urlBuilder = sshRemoteURL
 	}
 
@@ -97,31 +99,75 @@ func (m *Manager) LoadRepository(name string, t RepositoryType) (*Repository, er
 		return repo, nil
 	}
 
-	switch t {
-	case RepositoryTypeCore:
-		if !util.InStrings(name, m.cfg.Repositories.Core) {
-			return nil, ErrUnconfiguredRepository
-		}
-	case RepositoryTypeController:
-		if !util.InStrings(name, m.cfg.Repositories.Services) {
-			return nil, ErrUnconfiguredRepository
-		}
+	repos, err := m.configuredRepositories(t)
+	if err != nil {
+		return nil, err
+	}
+	if config.FindRepository(*repos, name) == nil {
+		return nil, ErrUnconfiguredRepository
 	}
 
 	return m.AddRepository(name, t)
 }
 
+// configuredRepositories returns the configured list of repositories of a
+// given type.
+func (m *Manager) configuredRepositories(t RepositoryType) (*[]config.RepositoryConfig, error) {
+	if t == RepositoryTypeUnknown {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRepositoryType, t)
+	}
+	return m.cfg.Repositories.ByType(t.String())
+}
+
+// repositoryConfig returns the configuration of a repository. If the repository
+// is not configured yet, it returns a configuration following the ackdev
+// conventions.
+func (m *Manager) repositoryConfig(name string, t RepositoryType) *config.RepositoryConfig {
+	repos, err := m.configuredRepositories(t)
+	if err == nil {
+		if repoConfig := config.FindRepository(*repos, name); repoConfig != nil {
+			return repoConfig
+		}
+	}
+	return &config.RepositoryConfig{Name: name}
+}
+
 // AddRepository creates a new Repository object and adds it to the cache.
 func (m *Manager) AddRepository(name string, t RepositoryType) (*Repository, error) {
+	repoConfig := m.repositoryConfig(name, t)
+	if repoConfig.Type != "" {
+		var err error
+		t, err = GetRepositoryTypeFromString(repoConfig.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration for repository %s: %v", name, err)
+		}
+	}
 	repo := NewRepository(name, t)
 
 	// set expected fork name
 	repo.ExpectedForkName = repo.Name
-	if m.cfg.Github.ForkPrefix != "" {
+	if repoConfig.ForkName != "" {
+		repo.ExpectedForkName = repoConfig.ForkName
+	} else if m.cfg.Github.ForkPrefix != "" {
 		repo.ExpectedForkName = fmt.Sprintf("%s%s", m.cfg.Github.ForkPrefix, repo.Name)
 	}
 
+	repo.UpstreamOwner = github.ACKOrg
+	if repoConfig.UpstreamOwner != "" {
+		repo.UpstreamOwner = repoConfig.UpstreamOwner
+	}
+	repo.DefaultBranch = defaultBranch
+	if repoConfig.DefaultBranch != "" {
+		repo.DefaultBranch = repoConfig.DefaultBranch
+	}
+
 	repo.FullPath = filepath.Join(m.cfg.RootDirectory, repo.Name)
+	if repoConfig.Path != "" {
+		repo.FullPath = repoConfig.Path
+		if !filepath.IsAbs(repo.FullPath) {
+			repo.FullPath = filepath.Join(m.cfg.RootDirectory, repo.FullPath)
+		}
+	}
 	gitRepo, err := m.git.Open(repo.FullPath)
 	if err == git.ErrRepositoryNotExists {
 		m.repoCache[name] = repo
@@ -149,13 +195,19 @@ func (m *Manager) AddRepository(name string, t RepositoryType) (*Repository, err
 func (m *Manager) LoadAll() error {
 	// collect repositories from config
 	for _, coreRepo := range m.cfg.Repositories.Core {
-		_, err := m.LoadRepository(coreRepo, RepositoryTypeCore)
+		_, err := m.LoadRepository(coreRepo.Name, RepositoryTypeCore)
+		if err != nil {
+			return err
+		}
+	}
+	for _, toolingRepo := range m.cfg.Repositories.Tooling {
+		_, err := m.LoadRepository(toolingRepo.Name, RepositoryTypeTooling)
 		if err != nil {
 			return err
 		}
 	}
-	for _, serviceName := range m.cfg.Repositories.Services {
-		_, err := m.LoadRepository(serviceName, RepositoryTypeController)
+	for _, service := range m.cfg.Repositories.Services {
+		_, err := m.LoadRepository(service.Name, RepositoryTypeController)
 		if err != nil {
 			return err
 		}
@@ -175,7 +227,10 @@ func (m *Manager) getRepository(repoName string) (*Repository, error) {
 // List returns the list of all the cached repositories
 func (m *Manager) List(filters ...Filter) []*Repository {
 	repos := []*Repository{}
-	repoNames := append(m.cfg.Repositories.Core, m.cfg.Repositories.Services...)
+	repoNames := []string{}
+	repoNames = append(repoNames, config.RepositoryNames(m.cfg.Repositories.Core)...)
+	repoNames = append(repoNames, config.RepositoryNames(m.cfg.Repositories.Tooling)...)
+	repoNames = append(repoNames, config.RepositoryNames(m.cfg.Repositories.Services)...)
 mainLoop:
 	for _, repoName := range repoNames {
 		repo, err := m.getRepository(repoName)
@@ -231,7 +286,7 @@ func (m *Manager) clone(ctx context.Context, repoName string) error {
 	// Add upstream remote
 	_, err = gitRepo.CreateRemote(&gitconfig.RemoteConfig{
 		Name: upstreamRemoteName,
-		URLs: []string{m.urlBuilder(github.ACKOrg, repo.Name)},
+		URLs: []string{m.urlBuilder(repo.UpstreamOwner, repo.Name)},
 	})
 
 	if err != nil {
@@ -247,7 +302,7 @@ func (m *Manager) clone(ctx context.Context, repoName string) error {
 func (m *Manager) EnsureFork(ctx context.Context, repo *Repository) error {
 	// TODO(hilaly): m.log.SetLevel(logrus.DebugLevel)
 
-	fork, err := m.ghc.GetUserRepositoryFork(ctx, m.cfg.Github.Username, repo.Name)
+	fork, err := m.ghc.GetUserRepositoryFork(ctx, m.cfg.Github.Username, repo.UpstreamOwner, repo.Name)
 	if err == nil {
 		if *fork.Name != repo.ExpectedForkName {
 			err = m.ghc.RenameRepository(ctx, m.cfg.Github.Username, *fork.Name, repo.ExpectedForkName)
@@ -256,7 +311,7 @@ func (m *Manager) EnsureFork(ctx context.Context, repo *Repository) error {
 			}
 		}
 	} else if err == github.ErrForkNotFound {
-		err = m.ghc.ForkRepository(ctx, repo.Name)
+		err = m.ghc.ForkRepository(ctx, repo.UpstreamOwner, repo.Name)
 		if err != nil {
 			return err
 		}
@@ -326,7 +381,7 @@ func (m *Manager) EnsureRemotes(ctx context.Context, repo *Repository) error {
 		}
 	}
 
-	expectedUpstreamURL := m.urlBuilder(github.ACKOrg, repo.Name)
+	expectedUpstreamURL := m.urlBuilder(repo.UpstreamOwner, repo.Name)
 	// Then check that one of the upstream URLs points to the original
 	// repository
 	upstreamURLs, ok := remotes[upstreamRemoteName]
@@ -340,6 +395,45 @@ func (m *Manager) EnsureRemotes(ctx context.Context, repo *Repository) error {
 	return nil
 }
 
+// DeleteLocal deletes the local clone of a repository, after verifying that
+// it doesn't contain any work that would be lost.
+func (m *Manager) DeleteLocal(repo *Repository) error {
+	return m.deleteLocal(repo, repo.CheckSafeToDelete)
+}
+
+// DeleteLocalWithFork deletes the local clone of a repository whose fork is
+// deleted too, after verifying that its branches were pushed to upstream.
+func (m *Manager) DeleteLocalWithFork(repo *Repository) error {
+	return m.deleteLocal(repo, repo.CheckSafeToDeleteWithFork)
+}
+
+func (m *Manager) deleteLocal(repo *Repository, checkSafeToDelete func() error) error {
+	if !repo.Cloned() {
+		return nil
+	}
+	err := checkSafeToDelete()
+	if err != nil {
+		return fmt.Errorf("cannot delete %s: %w", repo.FullPath, err)
+	}
+	err = os.RemoveAll(repo.FullPath)
+	if err != nil {
+		return err
+	}
+	repo.gitRepo = nil
+	repo.GitHead = ""
+	return nil
+}
+
+// DeleteFork deletes the user fork of a repository. It returns
+// github.ErrForkNotFound if the user doesn't own a fork of the repository.
+func (m *Manager) DeleteFork(ctx context.Context, repo *Repository) error {
+	fork, err := m.ghc.GetUserRepositoryFork(ctx, m.cfg.Github.Username, repo.UpstreamOwner, repo.Name)
+	if err != nil {
+		return err
+	}
+	return m.ghc.DeleteRepository(ctx, m.cfg.Github.Username, fork.GetName())
+}
+
 // EnsureAll ensures all cached repositories.
 func (m *Manager) EnsureAll(ctx context.Context) error {
 	for _, repo := range m.repoCache {
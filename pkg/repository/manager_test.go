@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	gogithub "github.com/google/go-github/v35/github"
@@ -25,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/src-d/go-git.v4"
 	gitconfig "gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 
 	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
@@ -124,6 +127,86 @@ func TestManager_LoadRepository(t *testing.T) {
 	}
 }
 
+func TestManager_AddRepository(t *testing.T) {
+	rootDir := filepath.Join(os.TempDir(), "ack")
+	runtimePath := filepath.Join(os.TempDir(), "src", "runtime")
+
+	fakeGit := &mocks.OpenCloner{}
+	fakeGit.On("Open", filepath.Join(rootDir, "s3-controller")).Return(nil, git.ErrRepositoryNotExists)
+	fakeGit.On("Open", filepath.Join(rootDir, "forks", "ecr")).Return(nil, git.ErrRepositoryNotExists)
+	fakeGit.On("Open", runtimePath).Return(nil, git.ErrRepositoryNotExists)
+
+	cfg := testutil.NewConfig("s3")
+	cfg.RootDirectory = rootDir
+	cfg.Repositories.Core[0].Path = runtimePath
+	cfg.Repositories.Services = append(cfg.Repositories.Services, config.RepositoryConfig{
+		Name:          "ecr",
+		Path:          "forks/ecr",
+		ForkName:      "my-ecr",
+		UpstreamOwner: "someone",
+		DefaultBranch: "dev",
+	})
+
+	tests := []struct {
+		name     string
+		repoName string
+		repoType RepositoryType
+		want     *Repository
+	}{
+		{
+			name:     "default conventions",
+			repoName: "s3",
+			repoType: RepositoryTypeController,
+			want: &Repository{
+				Name:             "s3-controller",
+				Type:             RepositoryTypeController,
+				ExpectedForkName: "ack-s3-controller",
+				UpstreamOwner:    github.ACKOrg,
+				DefaultBranch:    "main",
+				FullPath:         filepath.Join(rootDir, "s3-controller"),
+			},
+		},
+		{
+			name:     "all conventions overridden",
+			repoName: "ecr",
+			repoType: RepositoryTypeController,
+			want: &Repository{
+				Name:             "ecr-controller",
+				Type:             RepositoryTypeController,
+				ExpectedForkName: "my-ecr",
+				UpstreamOwner:    "someone",
+				DefaultBranch:    "dev",
+				FullPath:         filepath.Join(rootDir, "forks", "ecr"),
+			},
+		},
+		{
+			name:     "absolute path",
+			repoName: "runtime",
+			repoType: RepositoryTypeCore,
+			want: &Repository{
+				Name:             "runtime",
+				Type:             RepositoryTypeCore,
+				ExpectedForkName: "ack-runtime",
+				UpstreamOwner:    github.ACKOrg,
+				DefaultBranch:    "main",
+				FullPath:         runtimePath,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manager{
+				cfg:       cfg,
+				git:       fakeGit,
+				repoCache: make(map[string]*Repository),
+			}
+			repo, err := m.AddRepository(tt.repoName, tt.repoType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, repo)
+		})
+	}
+}
+
 func TestManager_LoadAll(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -137,6 +220,10 @@ func TestManager_LoadAll(t *testing.T) {
 	fakeGit.On("Open", "s3-controller").Return(nil, git.ErrRepositoryNotExists)
 	fakeGit.On("Open", "ecr-controller").Return(nil, bytes.ErrTooLarge)
 	fakeGit.On("Open", "sagemaker-controller").Return(nil, bytes.ErrTooLarge)
+	fakeGit.On("Open", "kind").Return(testRepo, nil)
+
+	toolingConfig := testutil.NewConfig()
+	toolingConfig.Repositories.Tooling = config.NewRepositoryConfigs("kind")
 
 	type fields struct {
 		cfg       *config.Config
@@ -168,6 +255,15 @@ func TestManager_LoadAll(t *testing.T) {
 			// It should just show that there are no active branches locally.
 			wantErr: false,
 		},
+		{
+			name: "tooling repositories",
+			fields: fields{
+				cfg:       toolingConfig,
+				git:       fakeGit,
+				repoCache: make(map[string]*Repository),
+			},
+			wantErr: false,
+		},
 		{
 			name: "unexpected repository error",
 			fields: fields{
@@ -331,11 +427,13 @@ func TestManager_EnsureFork(t *testing.T) {
 		"GetUserRepositoryFork",
 		testingCtx,
 		"ack-bot",
+		github.ACKOrg,
 		"s3-controller",
 	).Return(nil, github.ErrForkNotFound)
 	fakeGithubClient.On(
 		"ForkRepository",
 		testingCtx,
+		github.ACKOrg,
 		"s3-controller",
 	).Return(errors.New("unknown error"))
 
@@ -344,6 +442,7 @@ func TestManager_EnsureFork(t *testing.T) {
 		"GetUserRepositoryFork",
 		testingCtx,
 		"ack-bot",
+		github.ACKOrg,
 		"sagemaker-controller",
 	).Return(&gogithub.Repository{Name: stringPtr("sagemaker-controller")}, nil)
 	fakeGithubClient.On(
@@ -359,11 +458,13 @@ func TestManager_EnsureFork(t *testing.T) {
 		"GetUserRepositoryFork",
 		testingCtx,
 		"ack-bot",
+		github.ACKOrg,
 		"ecr-controller",
 	).Return(nil, github.ErrForkNotFound)
 	fakeGithubClient.On(
 		"ForkRepository",
 		testingCtx,
+		github.ACKOrg,
 		"ecr-controller",
 	).Return(nil)
 	fakeGithubClient.On(
@@ -400,6 +501,7 @@ func TestManager_EnsureFork(t *testing.T) {
 				repo: &Repository{
 					Name:             "s3-controller",
 					ExpectedForkName: "s3-sagemaker-controller",
+					UpstreamOwner:    github.ACKOrg,
 				},
 			},
 			wantErr: true,
@@ -415,6 +517,7 @@ func TestManager_EnsureFork(t *testing.T) {
 				repo: &Repository{
 					Name:             "sagemaker-controller",
 					ExpectedForkName: "ack-sagemaker-controller",
+					UpstreamOwner:    github.ACKOrg,
 				},
 			},
 			wantErr: false,
@@ -430,6 +533,7 @@ func TestManager_EnsureFork(t *testing.T) {
 				repo: &Repository{
 					Name:             "ecr-controller",
 					ExpectedForkName: "ack-ecr-controller",
+					UpstreamOwner:    github.ACKOrg,
 				},
 			},
 			wantErr: false,
@@ -449,3 +553,25 @@ func TestManager_EnsureFork(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_DeleteLocalWithFork(t *testing.T) {
+	repo, cleanup := newPushedRepository(t)
+	defer cleanup()
+	m := &Manager{}
+
+	// master is only pushed to origin, which is deleted with the fork
+	err := m.DeleteLocalWithFork(repo)
+	assert.True(t, errors.Is(err, ErrUnpushedBranches), "expected %v, got %v", ErrUnpushedBranches, err)
+	assert.True(t, repo.Cloned())
+
+	head, err := repo.gitRepo.Head()
+	require.NoError(t, err)
+	err = repo.gitRepo.Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewRemoteReferenceName(upstreamRemoteName, "main"), head.Hash(),
+	))
+	require.NoError(t, err)
+	require.NoError(t, m.DeleteLocalWithFork(repo))
+	assert.False(t, repo.Cloned())
+	_, err = os.Stat(repo.FullPath)
+	assert.True(t, os.IsNotExist(err))
+}
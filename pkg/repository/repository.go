@@ -17,6 +17,8 @@ import (
 	"fmt"
 
 	"gopkg.in/src-d/go-git.v4"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/config"
 )
 
 // NewRepository returns a pointer to a new repository.
@@ -41,12 +43,22 @@ type Repository struct {
 	Type RepositoryType
 	// Expected fork name. Generally looking like ack-sagemaker
 	ExpectedForkName string
+	// Github owner of the upstream repository. Generally aws-controllers-k8s
+	UpstreamOwner string
+	// Default branch of the upstream repository
+	DefaultBranch string
 	// Expected local full path
 	FullPath string
 	// Git HEAD commit or current branch
 	GitHead string
 }
 
+// UpstreamDefaultBranch returns the name of the upstream remote and of the
+// upstream default branch, for instance upstream and main.
+func (r *Repository) UpstreamDefaultBranch() (string, string) {
+	return upstreamRemoteName, r.DefaultBranch
+}
+
 func httpsRemoteURL(owner, name string) string {
 	return fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
 }
@@ -54,3 +66,12 @@ func httpsRemoteURL(owner, name string) string {
 func sshRemoteURL(owner, name string) string {
 	return fmt.Sprintf("git@github.com:%s/%s.git", owner, name)
 }
+
+// RemoteURL returns the URL of a Github repository, using the SSH protocol if
+// the configuration contains an SSH key path and HTTPS otherwise.
+func RemoteURL(cfg *config.Config, owner, name string) string {
+	if cfg.Git.SSHKeyPath != "" {
+		return sshRemoteURL(owner, name)
+	}
+	return httpsRemoteURL(owner, name)
+}
@@ -13,6 +13,16 @@
 
 package repository
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnsupportedRepositoryType error = errors.New("unsupported repository type")
+)
+
 type RepositoryType int
 
 const (
@@ -27,23 +37,25 @@ func (rt RepositoryType) String() string {
 	switch rt {
 	case RepositoryTypeCore:
 		return "core"
+	case RepositoryTypeTooling:
+		return "tooling"
 	case RepositoryTypeController:
 		return "controller"
-	case RepositoryTypeUnknown:
-		return "UNKNOWN"
 	default:
-		panic("unsupported repository type")
+		return "UNKNOWN"
 	}
 }
 
 // GetRepositoryTypeFromString casts a string to a RepositoryType
-func GetRepositoryTypeFromString(s string) RepositoryType {
-	switch s {
+func GetRepositoryTypeFromString(s string) (RepositoryType, error) {
+	switch strings.ToLower(s) {
 	case "core":
-		return RepositoryTypeCore
+		return RepositoryTypeCore, nil
+	case "tooling":
+		return RepositoryTypeTooling, nil
 	case "controller":
-		return RepositoryTypeController
+		return RepositoryTypeController, nil
 	default:
-		panic("unsupported repository type: " + s)
+		return RepositoryTypeUnknown, fmt.Errorf("%w: %s", ErrUnsupportedRepositoryType, s)
 	}
 }
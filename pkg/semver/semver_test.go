@@ -0,0 +1,88 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package semver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		version string
+		want    *Version
+		wantErr bool
+	}{
+		{version: "v0.15.2", want: &Version{Minor: 15, Patch: 2}},
+		{version: "1.2.3", want: &Version{Major: 1, Minor: 2, Patch: 3}},
+		{version: "v1.17", want: &Version{Major: 1, Minor: 17}},
+		{version: "v1.0.0-rc.1+build.5", want: &Version{Major: 1, Prerelease: "rc.1", Build: "build.5"}},
+		{version: "", wantErr: true},
+		{version: "v1.2.3.4", wantErr: true},
+		{version: "v01.2.3", wantErr: true},
+		{version: "v1.2.x", wantErr: true},
+		{version: "v1.2.3-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := Parse(tt.version)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidVersion))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	assert.Equal(t, "v1.17.0", MustParse("1.17").String())
+	assert.Equal(t, "v1.0.0-rc.1+build.5", MustParse("v1.0.0-rc.1+build.5").String())
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v0.15.2", "v0.15.2", 0},
+		{"v0.15.2", "v0.16.0", -1},
+		{"v0.10.0", "v0.9.0", 1},
+		{"v1.0.0", "v1.0.0-rc.1", 1},
+		{"v1.0.0-rc.2", "v1.0.0-rc.10", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-1", "v1.0.0-alpha", -1},
+		{"v1.0.0+build", "v1.0.0", 0},
+		{"not-a-version", "v0.0.1", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, Compare(tt.a, tt.b))
+		})
+	}
+}
+
+func TestSortAndLatest(t *testing.T) {
+	versions := []string{"v0.10.0", "v0.2.0", "v1.0.0-rc.1", "latest", "v0.9.1"}
+	assert.Equal(t, "v0.10.0", Latest(versions))
+
+	Sort(versions)
+	assert.Equal(t, []string{"latest", "v0.2.0", "v0.9.1", "v0.10.0", "v1.0.0-rc.1"}, versions)
+
+	assert.Equal(t, "", Latest([]string{"v1.0.0-rc.1", "main"}))
+}
@@ -0,0 +1,49 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// NewFakeBinary writes an executable shell script named name in dir and
+// returns its path. Every invocation of the script appends its arguments to
+// <dir>/<name>.log before running the given script body.
+func NewFakeBinary(dir, name, body string) (string, error) {
+	path := filepath.Join(dir, name)
+	logPath := filepath.Join(dir, name+".log")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> '%s'\n%s\n", logPath, body)
+	err := ioutil.WriteFile(path, []byte(script), 0755)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// FakeBinaryCalls returns the arguments of each invocation of a fake binary
+// created by NewFakeBinary.
+func FakeBinaryCalls(dir, name string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name+".log"))
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSuffix(string(b), "\n")
+	if content == "" {
+		return []string{}, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
@@ -19,11 +19,11 @@ import "github.com/aws-controllers-k8s/dev-tools/pkg/config"
 func NewConfig(services ...string) *config.Config {
 	return &config.Config{
 		Repositories: config.RepositoriesConfig{
-			Core: []string{
+			Core: config.NewRepositoryConfigs(
 				"runtime",
 				"code-generator",
-			},
-			Services: services,
+			),
+			Services: config.NewRepositoryConfigs(services...),
 		},
 		Github: config.GithubConfig{
 			ForkPrefix: "ack-",
@@ -14,8 +14,12 @@
 package testutil
 
 import (
+	"io/ioutil"
+	"path/filepath"
+
 	"gopkg.in/src-d/go-billy.v4/memfs"
 	"gopkg.in/src-d/go-git.v4"
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
@@ -66,3 +70,45 @@ func NewInMemoryGitRepository() (*git.Repository, error) {
 	}
 	return repo, nil
 }
+
+// NewGitRepository initialises a git repository in the given path, containing
+// one commit and the given remotes.
+func NewGitRepository(path string, remotes map[string]string) (*git.Repository, error) {
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(path, "ramanujan_serie.txt"), []byte("1 + 2 + 3 + 4 + ... = -1/12"), 0644)
+	if err != nil {
+		return nil, err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Add("ramanujan_serie.txt")
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Commit("first commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "Srinivasa Ramanujan",
+			Email: "sramanujan@1729",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, url := range remotes {
+		_, err = repo.CreateRemote(&gitconfig.RemoteConfig{
+			Name: name,
+			URLs: []string{url},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/asyncexec"
+)
+
+// Tool is a command line tool invoked by ackdev, for example kind, kubectl or
+// helm. The binary can be replaced by a fake one in tests.
+type Tool struct {
+	// Binary is the name or the path of the tool binary.
+	Binary string
+	// Dir is the working directory of the tool. If empty, the tool runs in
+	// the current directory.
+	Dir string
+	// Env contains environment variables, in the form "key=value", added to
+	// the ackdev environment.
+	Env []string
+}
+
+// New returns a new tool invoking the given binary.
+func New(binary string, env ...string) *Tool {
+	return &Tool{
+		Binary: binary,
+		Env:    env,
+	}
+}
+
+// InDir returns a copy of the tool running in the given directory.
+func (t *Tool) InDir(dir string) *Tool {
+	c := *t
+	c.Dir = dir
+	return &c
+}
+
+func (t *Tool) command(args []string) *exec.Cmd {
+	cmd := exec.Command(t.Binary, args...)
+	cmd.Dir = t.Dir
+	if len(t.Env) > 0 {
+		cmd.Env = append(os.Environ(), t.Env...)
+	}
+	return cmd
+}
+
+// Run runs the tool with the given arguments, streaming its output to out if
+// it is not nil.
+func (t *Tool) Run(ctx context.Context, out asyncexec.Output, args ...string) error {
+	opts := []asyncexec.Option{asyncexec.WithContext(ctx)}
+	if out != nil {
+		opts = append(opts, asyncexec.WithOutput(out))
+	}
+	result, err := asyncexec.New(t.command(args), opts...).Run()
+	if err != nil {
+		return t.error(args, result, err)
+	}
+	return nil
+}
+
+// Output runs the tool with the given arguments and returns its stdout.
+func (t *Tool) Output(ctx context.Context, args ...string) ([]byte, error) {
+	out := &bufferOutput{}
+	err := t.Run(ctx, out, args...)
+	if err != nil {
+		return nil, err
+	}
+	return out.stdout.Bytes(), nil
+}
+
+// error wraps an error returned when running the tool, adding the last lines
+// written to stderr.
+func (t *Tool) error(args []string, result *asyncexec.Result, err error) error {
+	command := strings.Join(append([]string{t.Binary}, args...), " ")
+	if result == nil || len(result.Stderr) == 0 {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return fmt.Errorf("%s: %w: %s", command, err, strings.Join(result.Stderr, "\n"))
+}
+
+// bufferOutput collects the stdout lines of a tool and discards its stderr.
+type bufferOutput struct {
+	mu     sync.Mutex
+	stdout bytes.Buffer
+}
+
+func (o *bufferOutput) WriteStdout(line []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stdout.Write(line)
+	return o.stdout.WriteByte('\n')
+}
+
+func (o *bufferOutput) WriteStderr(line []byte) error {
+	return nil
+}
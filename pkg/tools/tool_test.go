@@ -0,0 +1,57 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !windows
+
+package tools
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/dev-tools/pkg/testutil"
+)
+
+func TestTool_Output(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-tools")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	binary, err := testutil.NewFakeBinary(dir, "tool", `echo "$@"; pwd; echo "$ACKDEV_TEST"; echo "ignored" >&2`)
+	require.NoError(t, err)
+
+	out, err := New(binary, "ACKDEV_TEST=value").InDir(dir).Output(context.Background(), "get", "clusters")
+	require.NoError(t, err)
+	assert.Equal(t, "get clusters\n"+dir+"\nvalue\n", string(out))
+}
+
+func TestTool_Run_error(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-tools")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	binary, err := testutil.NewFakeBinary(dir, "tool", `echo "something went wrong" >&2; exit 2`)
+	require.NoError(t, err)
+
+	err = New(binary).Run(context.Background(), nil, "create")
+	require.Error(t, err)
+	assert.Equal(t, binary+" create: exit status 2: something went wrong", err.Error())
+}
@@ -1,7 +1,9 @@
 package util
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	git "gopkg.in/src-d/go-git.v4"
 	gitconfig "gopkg.in/src-d/go-git.v4/config"
@@ -36,3 +38,33 @@ func UpdateRepositoryRemotes(repo *git.Repository, name string, URLs []string) e
 	}
 	return repo.Storer.SetConfig(cfg)
 }
+
+var (
+	ErrNotGithubURL = errors.New("not a Github repository URL")
+)
+
+var githubURLPrefixes = []string{
+	"https://github.com/",
+	"http://github.com/",
+	"ssh://git@github.com/",
+	"git@github.com:",
+	"git://github.com/",
+}
+
+// ParseGithubURL takes a Github repository URL (HTTPS or SSH) and returns the
+// repository owner and name.
+func ParseGithubURL(url string) (string, string, error) {
+	for _, prefix := range githubURLPrefixes {
+		if !strings.HasPrefix(url, prefix) {
+			continue
+		}
+		path := strings.TrimSuffix(strings.TrimPrefix(url, prefix), "/")
+		path = strings.TrimSuffix(path, ".git")
+		parts := strings.Split(path, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", ErrNotGithubURL
+		}
+		return parts[0], parts[1], nil
+	}
+	return "", "", ErrNotGithubURL
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGithubURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantOwner string
+		wantName  string
+		wantErr   bool
+	}{
+		{
+			name:      "https url",
+			url:       "https://github.com/aws-controllers-k8s/runtime.git",
+			wantOwner: "aws-controllers-k8s",
+			wantName:  "runtime",
+		},
+		{
+			name:      "https url without .git suffix",
+			url:       "https://github.com/aws-controllers-k8s/s3-controller",
+			wantOwner: "aws-controllers-k8s",
+			wantName:  "s3-controller",
+		},
+		{
+			name:      "scp-like ssh url",
+			url:       "git@github.com:ack-bot/ack-runtime.git",
+			wantOwner: "ack-bot",
+			wantName:  "ack-runtime",
+		},
+		{
+			name:      "ssh url",
+			url:       "ssh://git@github.com/ack-bot/ack-s3-controller.git",
+			wantOwner: "ack-bot",
+			wantName:  "ack-s3-controller",
+		},
+		{
+			name:    "non github url",
+			url:     "https://gitlab.com/aws-controllers-k8s/runtime.git",
+			wantErr: true,
+		},
+		{
+			name:    "missing repository name",
+			url:     "https://github.com/aws-controllers-k8s",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, name, err := ParseGithubURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGithubURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantOwner, owner)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package watch detects changes of source files by periodically scanning
+// directories.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultInterval is the time between two scans of the watched
+	// directories.
+	DefaultInterval = 500 * time.Millisecond
+	// DefaultDebounce is the time without changes waited before reporting
+	// changes, so that rapid saves trigger a single event.
+	DefaultDebounce = time.Second
+)
+
+// Option is a function modifying a Watcher.
+type Option func(*Watcher)
+
+// WithInterval sets the time between two scans of the watched directories.
+func WithInterval(d time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+// WithDebounce sets the time without changes waited before reporting changes.
+func WithDebounce(d time.Duration) Option {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// WithExtensions only watches the files with the given extensions, e.g ".go".
+func WithExtensions(extensions ...string) Option {
+	return func(w *Watcher) {
+		w.extensions = extensions
+	}
+}
+
+// Watcher watches the files of a list of directories, recursively.
+type Watcher struct {
+	dirs       []string
+	extensions []string
+	interval   time.Duration
+	debounce   time.Duration
+}
+
+// fileState is used to detect file modifications.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// New instantiates a new Watcher watching the given directories. Missing
+// directories are watched too, and their creation is reported as a change.
+func New(dirs []string, opts ...Option) *Watcher {
+	w := &Watcher{
+		dirs:     dirs,
+		interval: DefaultInterval,
+		debounce: DefaultDebounce,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Watch scans the watched directories until the context is done, and sends the
+// sorted list of created, modified and removed files once they stop changing
+// for the debounce duration. The returned channel is closed when the context
+// is done.
+func (w *Watcher) Watch(ctx context.Context) <-chan []string {
+	changes := make(chan []string)
+	// the initial state is scanned before returning, so that all the changes
+	// made after Watch returns are reported.
+	state := w.scan()
+	go func() {
+		defer close(changes)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		pending := map[string]bool{}
+		var lastChange time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newState := w.scan()
+			for _, path := range diff(state, newState) {
+				pending[path] = true
+				lastChange = time.Now()
+			}
+			state = newState
+			if len(pending) == 0 || time.Since(lastChange) < w.debounce {
+				continue
+			}
+
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			select {
+			case <-ctx.Done():
+				return
+			case changes <- paths:
+			}
+			pending = map[string]bool{}
+		}
+	}()
+	return changes
+}
+
+// scan returns the state of the watched files. Files that can't be read, for
+// example because they were removed during the scan, are ignored.
+func (w *Watcher) scan() map[string]fileState {
+	state := map[string]fileState{}
+	for _, dir := range w.dirs {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !w.watched(path) {
+				return nil
+			}
+			state[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return state
+}
+
+func (w *Watcher) watched(path string) bool {
+	if len(w.extensions) == 0 {
+		return true
+	}
+	for _, ext := range w.extensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the files that differ between two states.
+func diff(old, new map[string]fileState) []string {
+	changed := []string{}
+	for path, s := range new {
+		if o, ok := old[path]; !ok || o != s {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, changes <-chan []string) []string {
+	select {
+	case paths := <-changes:
+		return paths
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+	}
+	return nil
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ackdev-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pkgDir := filepath.Join(dir, "pkg")
+	require.NoError(t, os.MkdirAll(filepath.Join(pkgDir, "resource"), os.ModePerm))
+	existing := filepath.Join(pkgDir, "resource", "sdk.go")
+	require.NoError(t, ioutil.WriteFile(existing, []byte("package resource"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := New(
+		[]string{pkgDir, filepath.Join(dir, "cmd")},
+		WithInterval(10*time.Millisecond),
+		WithDebounce(100*time.Millisecond),
+		WithExtensions(".go"),
+	)
+	changes := w.Watch(ctx)
+
+	// rapid saves are reported at once, ignored files are not reported
+	created := filepath.Join(dir, "cmd", "controller", "main.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(created), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(created, []byte("package main"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pkgDir, "README.md"), []byte("# pkg"), 0644))
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, ioutil.WriteFile(existing, []byte("package resource\n\n// updated"), 0644))
+	assert.Equal(t, []string{created, existing}, receive(t, changes))
+
+	require.NoError(t, os.Remove(existing))
+	assert.Equal(t, []string{existing}, receive(t, changes))
+
+	cancel()
+	_, ok := <-changes
+	assert.False(t, ok)
+}